@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// edgePoint — точка сети: вершина (from == to) или точка на ребре from–to
+// на расстоянии offset от from
+type edgePoint struct {
+	from, to int
+	offset   float64
+	length   float64
+}
+
+// Функция для вычисления времени проезда от точки сети до всех вершин.
+// Сеть считается неориентированной: от точки на ребре можно выехать к любому его концу.
+func (p edgePoint) distances(distMatrix DistanceMatrix) []float64 {
+	result := make([]float64, len(distMatrix.Vertices))
+	for k := range result {
+		if p.from == p.to {
+			result[k] = distMatrix.Dist[p.from][k]
+			continue
+		}
+		result[k] = math.Min(p.offset+distMatrix.Dist[p.from][k], p.length-p.offset+distMatrix.Dist[p.to][k])
+	}
+	return result
+}
+
+// Функция для описания точки сети в отчёте
+func (p edgePoint) describe(vertices []string) string {
+	if p.from == p.to || p.offset == 0 {
+		return fmt.Sprintf("вершина %s", vertices[p.from])
+	}
+	if p.offset == p.length {
+		return fmt.Sprintf("вершина %s", vertices[p.to])
+	}
+	return fmt.Sprintf("ребро %s:%s, %.2f от %s (доля %.3f)", vertices[p.from], vertices[p.to], p.offset, vertices[p.from], p.offset/p.length)
+}
+
+// Функция для перечисления точек-кандидатов на абсолютный центр (Хакими):
+// все вершины и точки рёбер, где возрастающая ветвь взвешенного расстояния
+// до одной вершины пересекает убывающую ветвь до другой. Только в них
+// верхняя огибающая расстояний может достигать локального минимума.
+func absoluteCenterCandidates(graph *Graph, distMatrix DistanceMatrix, demand []float64) []edgePoint {
+	n := graph.Len()
+	candidates := make([]edgePoint, 0, n)
+	for v := 0; v < n; v++ {
+		candidates = append(candidates, edgePoint{from: v, to: v})
+	}
+
+	for u := 0; u < n; u++ {
+		for _, arc := range graph.Arcs(u) {
+			v, length := arc.To, arc.Weight
+			// Каждое ребро рассматривается один раз; односторонние дуги пропускаются
+			if back, ok := graph.Edge(v, u); v <= u || !ok || back != length || length <= 0 {
+				continue
+			}
+			seen := make(map[float64]bool)
+			for k := 0; k < n; k++ {
+				for l := 0; l < n; l++ {
+					wk, wl := demand[k], demand[l]
+					if wk+wl == 0 || math.IsInf(distMatrix.Dist[u][k], 1) || math.IsInf(distMatrix.Dist[v][l], 1) {
+						continue
+					}
+					// wk*(t + d(u,k)) = wl*(length - t + d(v,l))
+					t := (wl*(length+distMatrix.Dist[v][l]) - wk*distMatrix.Dist[u][k]) / (wk + wl)
+					t = math.Round(t*1e6) / 1e6
+					if t <= 0 || t >= length || seen[t] {
+						continue
+					}
+					seen[t] = true
+					candidates = append(candidates, edgePoint{from: u, to: v, offset: t, length: length})
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// Функция для отбора кандидатов: точка ребра отбрасывается, если другая
+// точка того же ребра или его конец не дальше неё от каждой вершины
+// с ненулевым спросом. Замена такой точки на доминирующую не ухудшает
+// критерий p-центра ни при каком p, поэтому оптимум сохраняется.
+// Совпадающие точки тоже отбрасываются, вершины остаются всегда.
+func dominantCandidates(candidates []edgePoint, cost [][]float64, demand []float64) ([]edgePoint, [][]float64) {
+	const eps = 1e-9
+	// dominates сообщает, что кандидат b не дальше кандидата a от всех вершин со спросом
+	dominates := func(b, a int) bool {
+		for k, w := range demand {
+			if w > 0 && cost[b][k] > cost[a][k]+eps {
+				return false
+			}
+		}
+		return true
+	}
+
+	var kept []edgePoint
+	var keptCost [][]float64
+	for a, candidate := range candidates {
+		dominated := false
+		if candidate.from != candidate.to {
+			// Концы ребра — кандидаты-вершины с индексами from и to
+			dominated = dominates(candidate.from, a) || dominates(candidate.to, a)
+			for b := range candidates {
+				if dominated {
+					break
+				}
+				other := candidates[b]
+				if b == a || other.from != candidate.from || other.to != candidate.to || !dominates(b, a) {
+					continue
+				}
+				// Из взаимно доминирующих (совпадающих) точек остаётся первая
+				dominated = b < a || !dominates(a, b)
+			}
+		}
+		if !dominated {
+			kept = append(kept, candidate)
+			keptCost = append(keptCost, cost[a])
+		}
+	}
+	return kept, keptCost
+}
+
+// Наибольшее число узлов перебора при точном решении задачи p-центра
+// через покрытия; при превышении используется эвристика
+const pCenterSearchLimit = 2000000
+
+// Функция для точного решения взвешенной задачи p-центра, когда полный
+// перебор наборов слишком долог. Радиус ищется двоичным поиском среди
+// взвешенных расстояний кандидатов до вершин; для каждого радиуса
+// проверяется, покрывают ли p кандидатов все вершины со спросом. Перебор
+// ветвится по непокрытой вершине с наименьшим числом покрывающих
+// кандидатов. false — превышен pCenterSearchLimit.
+func exactPCenter(cost [][]float64, demand []float64, p int) (placement, bool) {
+	var radii []float64
+	for c := range cost {
+		for k, w := range demand {
+			if w > 0 && !math.IsInf(cost[c][k], 1) {
+				radii = append(radii, w*cost[c][k])
+			}
+		}
+	}
+	slices.Sort(radii)
+	radii = slices.Compact(radii)
+
+	nodes := 0
+	// cover подбирает пункты, покрывающие за radius все вершины со спросом
+	var cover func(radius float64, facilities []int) ([]int, bool)
+	cover = func(radius float64, facilities []int) ([]int, bool) {
+		nodes++
+		if nodes > pCenterSearchLimit {
+			return nil, false
+		}
+		branch, fewest := -1, len(cost)+1
+		for k, w := range demand {
+			if w == 0 || slices.ContainsFunc(facilities, func(f int) bool { return w*cost[f][k] <= radius }) {
+				continue
+			}
+			count := 0
+			for c := range cost {
+				if w*cost[c][k] <= radius {
+					count++
+				}
+			}
+			if count < fewest {
+				branch, fewest = k, count
+			}
+		}
+		if branch < 0 {
+			return facilities, true
+		}
+		if len(facilities) == p || fewest == 0 {
+			return nil, false
+		}
+		for c := range cost {
+			if demand[branch]*cost[c][branch] <= radius {
+				if found, ok := cover(radius, append(facilities, c)); ok {
+					return found, true
+				}
+			}
+		}
+		return nil, false
+	}
+
+	// Двоичный поиск наименьшего радиуса, при котором покрытие существует
+	best := placement{value: math.Inf(1), exact: true}
+	low, high := 0, len(radii)-1
+	for low <= high {
+		middle := (low + high) / 2
+		facilities, ok := cover(radii[middle], make([]int, 0, p))
+		if nodes > pCenterSearchLimit {
+			return placement{}, false
+		}
+		if ok {
+			best.facilities, best.value = slices.Clone(facilities), radii[middle]
+			high = middle - 1
+		} else {
+			low = middle + 1
+		}
+	}
+	if best.facilities == nil {
+		return best, true
+	}
+	// Недостающие до p пункты не меняют радиус
+	for c := 0; len(best.facilities) < min(p, len(cost)); c++ {
+		if !slices.Contains(best.facilities, c) {
+			best.facilities = append(best.facilities, c)
+		}
+	}
+	slices.Sort(best.facilities)
+	return best, true
+}
+
+// Результат поиска абсолютного центра
+type absoluteCenter struct {
+	points       []edgePoint
+	radius       float64 // наибольшее взвешенное время до ближайшей точки размещения
+	vertexRadius float64 // то же для лучшего размещения только в вершинах
+	vertices     []int
+	candidates   int  // число кандидатов после отбора доминирующих
+	exact        bool // размещение на рёбрах найдено полным перебором
+	vertexExact  bool // размещение в вершинах найдено полным перебором
+}
+
+// Функция для поиска абсолютного p-центра: размещение p пунктов в любых
+// точках сети, включая внутренние точки рёбер, по взвешенному критерию p-центра
+func solveAbsoluteCenter(graph *Graph, distMatrix DistanceMatrix, demand []float64, p int) absoluteCenter {
+	candidates := absoluteCenterCandidates(graph, distMatrix, demand)
+	cost := make([][]float64, len(candidates))
+	for i, candidate := range candidates {
+		cost[i] = candidate.distances(distMatrix)
+	}
+	candidates, cost = dominantCandidates(candidates, cost, demand)
+
+	objective := weightedPCenterObjective(demand)
+	// Первые graph.Len() кандидатов — вершины
+	vertexBest := solvePlacement(cost[:graph.Len()], p, objective)
+	best := solvePlacement(cost, p, objective)
+	if !best.exact {
+		if exact, ok := exactPCenter(cost, demand, p); ok {
+			best = exact
+		}
+	}
+	if !best.exact {
+		// Эвристика начинает и с размещения в вершинах, чтобы результат
+		// на рёбрах не оказался хуже допустимого решения в вершинах
+		if fromVertices := interchangePlacement(cost, vertexBest, objective); fromVertices.value < best.value {
+			best = fromVertices
+		}
+	}
+
+	center := absoluteCenter{
+		radius:       best.value,
+		vertexRadius: vertexBest.value,
+		vertices:     vertexBest.facilities,
+		candidates:   len(candidates),
+		exact:        best.exact,
+		vertexExact:  vertexBest.exact,
+	}
+	for _, c := range best.facilities {
+		center.points = append(center.points, candidates[c])
+	}
+	return center
+}
+
+// Функция для построения таблицы абсолютного центра для отчёта
+func absoluteCenterTable(vertices []string, center absoluteCenter) [][]string {
+	table := [][]string{{"Название", "Результат"}}
+	for i, point := range center.points {
+		table = append(table, []string{fmt.Sprintf("Точка размещения %d", i+1), point.describe(vertices)})
+	}
+	table = append(table,
+		[]string{"Радиус абсолютного центра", formatDistance(center.radius)},
+		[]string{"Лучшее размещение в вершинах", formatFacilities(vertices, center.vertices)},
+		[]string{"Радиус лучшего размещения в вершинах", formatDistance(center.vertexRadius)},
+	)
+
+	method := func(exact bool) string {
+		if exact {
+			return "полный перебор"
+		}
+		return "эвристика (жадный выбор и замены)"
+	}
+	table = append(table,
+		[]string{"Кандидатов на рёбрах и в вершинах", fmt.Sprintf("%d", center.candidates)},
+		[]string{"Метод для рёбер", method(center.exact)},
+		[]string{"Метод для вершин", method(center.vertexExact)},
+	)
+
+	if math.IsInf(center.vertexRadius, 1) || math.IsInf(center.radius, 1) {
+		return append(table, []string{"Выигрыш", "—"})
+	}
+	// Вершины входят в кандидаты, поэтому точный абсолютный центр не хуже
+	// размещения в вершинах; отрицательная разница возможна только из-за эвристики
+	improvement := center.vertexRadius - center.radius
+	if improvement < 0 {
+		return append(table, []string{"Выигрыш", "не определён: эвристика для рёбер не нашла размещения лучше, чем в вершинах"})
+	}
+	percent := 0.0
+	if center.vertexRadius > 0 {
+		percent = 100 * improvement / center.vertexRadius
+	}
+	gain := fmt.Sprintf("%.2f (%.1f%%)", improvement, percent)
+	switch {
+	case !center.vertexExact:
+		gain += ", оценка: размещение в вершинах найдено эвристикой"
+	case !center.exact:
+		gain += ", не меньше: размещение на рёбрах найдено эвристикой"
+	}
+	return append(table, []string{"Выигрыш", gain})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// Функция для генерации связной неориентированной сети со случайным спросом
+func generateAbsoluteCenterInstance(rng *rand.Rand, n int) (*Graph, DistanceMatrix, []float64) {
+	vertices := make([]string, n)
+	for i := range vertices {
+		vertices[i] = strconv.Itoa(i + 1)
+	}
+	graph := NewGraph(vertices)
+	for i := 1; i < n; i++ {
+		graph.SetUndirectedEdge(i, rng.Intn(i), float64(1+rng.Intn(20)))
+	}
+	for e := 0; e < n/2; e++ {
+		if i, j := rng.Intn(n), rng.Intn(n); i != j {
+			graph.SetUndirectedEdge(i, j, float64(1+rng.Intn(20)))
+		}
+	}
+	demand := make([]float64, n)
+	for i := range demand {
+		demand[i] = float64(rng.Intn(5000))
+	}
+	return graph, dijkstraAll(graph), demand
+}
+
+func TestAbsoluteCenterIsExact(t *testing.T) {
+	tests := []struct {
+		name string
+		n, p int
+	}{
+		{"1-центр", 7, 1},
+		{"2-центр", 7, 2},
+		{"3-центр", 7, 3},
+		{"3-центр, 8 вершин", 8, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(int64(tt.n*10 + tt.p)))
+			for instance := 0; instance < 5; instance++ {
+				graph, distMatrix, demand := generateAbsoluteCenterInstance(rng, tt.n)
+				candidates := absoluteCenterCandidates(graph, distMatrix, demand)
+				cost := make([][]float64, len(candidates))
+				for i, candidate := range candidates {
+					cost[i] = candidate.distances(distMatrix)
+				}
+				// Полный перебор по всем кандидатам без отбора
+				want := exactPlacement(cost, tt.p, weightedPCenterObjective(demand))
+
+				center := solveAbsoluteCenter(graph, distMatrix, demand, tt.p)
+				if !center.exact {
+					t.Fatalf("размещение на рёбрах найдено эвристикой (%d кандидатов)", center.candidates)
+				}
+				if center.candidates > len(candidates) {
+					t.Fatalf("после отбора %d кандидатов, до отбора %d", center.candidates, len(candidates))
+				}
+				if !nearlyEqual(center.radius, want.value) {
+					t.Fatalf("радиус %v, полный перебор по всем кандидатам %v", center.radius, want.value)
+				}
+				if center.radius > center.vertexRadius {
+					t.Fatalf("радиус на рёбрах %v больше радиуса в вершинах %v", center.radius, center.vertexRadius)
+				}
+			}
+		})
+	}
+}
+
+func TestExactPCenterMatchesEnumeration(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for instance := 0; instance < 50; instance++ {
+		distMatrix, demand := generatePlacementInstance(rng)
+		objective := weightedPCenterObjective(demand)
+		for p := 1; p <= 3; p++ {
+			got, ok := exactPCenter(distMatrix.Dist, demand, p)
+			want := exactPlacement(distMatrix.Dist, p, objective)
+			if !ok || len(got.facilities) != p || !nearlyEqual(got.value, want.value) || !nearlyEqual(objective(distMatrix.Dist, got.facilities), want.value) {
+				t.Fatalf("p = %d: %+v, ожидалось значение %v", p, got, want.value)
+			}
+		}
+	}
+}
+
+func nearlyEqual(a, b float64) bool {
+	return a == b || (a-b)*(a-b) <= 1e-18*(a*a+b*b)
+}
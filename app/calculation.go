@@ -6,6 +6,7 @@ import (
 	"math"
 	"math/rand"
 	"strconv"
+	"strings"
 
 	"gonum.org/v1/gonum/stat/distuv"
 )
@@ -57,95 +58,91 @@ type edge struct {
 	destination string
 }
 
-func generateRandomNetwork(points []string, distribution [][]string, randomNumber float64) [][]string {
-	matrixSize := len(points)
-	randomNetwork := make([][]string, matrixSize+1)
-
-	// Инициализация первой строки заголовков
-	randomNetwork[0] = make([]string, matrixSize+1)
-	randomNetwork[0][0] = ""
-	for i := 0; i < matrixSize; i++ {
-		randomNetwork[0][i+1] = points[i]
-	}
+// edgeDistribution — закон распределения времени проезда по ребру
+type edgeDistribution struct {
+	edge
+	normal bool
+	mean   float64 // E, выборочное среднее времени проезда
+	sd     float64 // Omega, для нормального
+	random float64 // пример нормированного случайного числа для отчёта
+	a, b   float64 // границы, для равномерного
+}
 
-	for i := 0; i < matrixSize; i++ {
-		randomNetwork[i+1] = make([]string, matrixSize+1)
-		randomNetwork[i+1][0] = points[i] // Заголовок строки
-		for j := 0; j < matrixSize; j++ {
-			randomNetwork[i+1][j+1] = "0" // Инициализация всех значений нулями
-		}
+// Функция для получения времени проезда по равномерному числу u методом обратной функции
+func (d edgeDistribution) quantile(u float64) float64 {
+	if d.normal {
+		z := distuv.UnitNormal.Quantile(clampUnit(u))
+		return math.Max(d.mean+d.sd*z, 0)
 	}
+	// a = E - √3·Ω бывает отрицательной, а отрицательное время на неориентированном
+	// ребре — это цикл отрицательного веса, поэтому обрезаем нулём, как и нормальный закон
+	return math.Max(d.a+(d.b-d.a)*u, 0)
+}
 
-	for i := 0; i < matrixSize; i++ {
-		for j := 0; j < matrixSize; j++ {
-			if i == j {
-				randomNetwork[i+1][j+1] = "0"
-				continue
-			}
-
-			key, r_key := fmt.Sprintf("%d:%d", i+1, j+1), fmt.Sprintf("%d:%d", j+1, i+1)
-			var dist []string
-			for _, d := range distribution {
-				if d[0] == key || d[0] == r_key {
-					dist = d
-					break
-				}
-			}
+// Генерирование случайной взвешенной сети. u[k] — равномерное число
+// из [0,1) для k-го ребра, вес ребра получается из него методом обратной функции.
+func generateRandomNetwork(points []string, distribution []edgeDistribution, u []float64) *Graph {
+	return networkFromWeights(points, distribution, randomWeights(distribution, u))
+}
 
-			if len(dist) == 0 {
-				continue
-			}
+// Функция для получения времён проезда по рёбрам из равномерных чисел u
+func randomWeights(distribution []edgeDistribution, u []float64) []float64 {
+	weights := make([]float64, len(distribution))
+	for k, d := range distribution {
+		weights[k] = d.quantile(u[k])
+	}
+	return weights
+}
 
-			if dist[1] == "нормальное" {
-				E, _ := strconv.ParseFloat(dist[2], 64)
-				Omega, _ := strconv.ParseFloat(dist[3], 64)
-				z := randomNumber
-				randomNetwork[i+1][j+1] = fmt.Sprintf("%.2f", E+Omega*z)
-			} else {
-				a, _ := strconv.ParseFloat(dist[5], 64)
-				b, _ := strconv.ParseFloat(dist[6], 64)
-				randomNetwork[i+1][j+1] = fmt.Sprintf("%.2f", a+(b-a)*randomNumber)
-			}
-			randomNetwork[j+1][i+1] = randomNetwork[i+1][j+1]
+// Функция для построения сети по заданным временам проезда: weights[k] —
+// время по k-му ребру из distribution, +Inf — ребро перекрыто
+func networkFromWeights(points []string, distribution []edgeDistribution, weights []float64) *Graph {
+	network := NewGraph(points)
+	for k, d := range distribution {
+		i, okOrigin := network.Index(d.origin)
+		j, okDestination := network.Index(d.destination)
+		if !okOrigin || !okDestination || i == j || math.IsInf(weights[k], 1) {
+			continue
 		}
+		// Округление как в таблице отчёта
+		network.SetUndirectedEdge(i, j, math.Round(weights[k]*100)/100)
 	}
-
-	return randomNetwork
+	return network
 }
 
-func dijkstraAll(graph [][]string) [][]string {
-	matrixSize := len(graph) - 1
-	distanceMatrix := make([][]string, matrixSize+1)
+// Функция для отсечения концов интервала, в которых квантиль нормального распределения бесконечен
+func clampUnit(u float64) float64 {
+	const eps = 1e-12
+	return math.Min(math.Max(u, eps), 1-eps)
+}
 
-	// Инициализация первой строки заголовков
-	distanceMatrix[0] = make([]string, matrixSize+1)
-	distanceMatrix[0][0] = ""
-	for i := 0; i < matrixSize; i++ {
-		distanceMatrix[0][i+1] = graph[0][i+1]
-	}
+func dijkstraAll(graph *Graph) DistanceMatrix {
+	distanceMatrix := newDistanceMatrix(graph.Vertices)
 
 	// Вычисление кратчайших путей для каждой вершины
-	for i := 0; i < matrixSize; i++ {
-		distanceMatrix[i+1] = dijkstra(graph, i+1)
-		distanceMatrix[i+1][0] = graph[i+1][0] // Заголовок строки
+	for i := range distanceMatrix.Dist {
+		distanceMatrix.Dist[i], distanceMatrix.Pred[i] = dijkstra(graph, i)
 	}
 	return distanceMatrix
 }
 
-func dijkstra(graph [][]string, start int) []string {
-	matrixSize := len(graph) - 1
-	distances := make([]float64, matrixSize+1)
-	visited := make([]bool, matrixSize+1)
+// Возвращает расстояния от start и предшественников на кратчайших путях
+func dijkstra(graph *Graph, start int) ([]float64, []int) {
+	matrixSize := graph.Len()
+	distances := make([]float64, matrixSize)
+	predecessors := make([]int, matrixSize)
+	visited := make([]bool, matrixSize)
 
 	for i := range distances {
 		distances[i] = math.Inf(1)
+		predecessors[i] = -1
 	}
 	distances[start] = 0
 
 	for i := 0; i < matrixSize; i++ {
 		minDist := math.Inf(1)
 		minIndex := -1
-		for j := 1; j <= matrixSize; j++ {
+		for j := 0; j < matrixSize; j++ {
 			if !visited[j] && distances[j] < minDist {
 				minDist = distances[j]
 				minIndex = j
@@ -158,29 +155,17 @@ func dijkstra(graph [][]string, start int) []string {
 
 		visited[minIndex] = true
 
-		for j := 1; j <= matrixSize; j++ {
-			if !visited[j] && graph[minIndex][j] != "0" {
-				edgeDist, _ := strconv.ParseFloat(graph[minIndex][j], 64)
-				newDist := distances[minIndex] + edgeDist
-				if newDist < distances[j] {
-					distances[j] = newDist
+		for _, arc := range graph.Arcs(minIndex) {
+			if !visited[arc.To] {
+				newDist := distances[minIndex] + arc.Weight
+				if newDist < distances[arc.To] {
+					distances[arc.To] = newDist
+					predecessors[arc.To] = minIndex
 				}
 			}
 		}
 	}
-
-	result := make([]string, matrixSize+1)
-	for i, dist := range distances {
-		if i == 0 {
-			continue
-		}
-		if dist == math.Inf(1) {
-			result[i] = "∞"
-		} else {
-			result[i] = fmt.Sprintf("%.2f", dist)
-		}
-	}
-	return result
+	return distances, predecessors
 }
 
 func a(E float64, Omega float64) float64 {
@@ -345,70 +330,89 @@ func calculateResults(data [][]string) ([][]string, [][]string) {
 	return results1, results2
 }
 
-func calculateDistribution(results1, results2 [][]string, edges []string) [][]string {
+func calculateDistribution(results1, results2 [][]string, edges []string) []edgeDistribution {
+	var distribution []edgeDistribution
 
-	var distribution [][]string
-	header := []string{"Ребро", "Распределение", "E", "Omega", "Рандом", "a", "b"}
-	distribution = append(distribution, header)
+	for i, edgeName := range edges {
+		var d edgeDistribution
+		parts := strings.Split(edgeName, ":")
+		if len(parts) == 2 {
+			d.edge = edge{origin: parts[0], destination: parts[1]}
+		}
 
-	for i, edge := range edges {
 		pN, _ := strconv.ParseFloat(results1[i+1][9], 64)
 		pR, _ := strconv.ParseFloat(results2[i+1][9], 64)
+		d.mean, _ = strconv.ParseFloat(results1[i+1][1], 64)
 		if !(pN > pR) {
-			randValue := math.Sqrt(-2*math.Log(rand.Float64())) * math.Cos(2*math.Pi*rand.Float64())
-			distribution = append(distribution, []string{
-				edge,
+			d.normal = true
+			d.sd, _ = strconv.ParseFloat(results1[i+1][2], 64)
+			d.random = math.Sqrt(-2*math.Log(rand.Float64())) * math.Cos(2*math.Pi*rand.Float64())
+		} else {
+			d.a, _ = strconv.ParseFloat(results2[i+1][3], 64)
+			d.b, _ = strconv.ParseFloat(results2[i+1][4], 64)
+		}
+		distribution = append(distribution, d)
+	}
+
+	return distribution
+}
+
+// Функция для перевода распределений рёбер в таблицу для отчёта
+func distributionTable(distribution []edgeDistribution) [][]string {
+	table := [][]string{{"Ребро", "Распределение", "E", "Omega", "Рандом", "a", "b"}}
+	for _, d := range distribution {
+		name := d.origin + ":" + d.destination
+		if d.normal {
+			table = append(table, []string{
+				name,
 				"нормальное",
-				results1[i+1][1],
-				results1[i+1][2],
-				fmt.Sprintf("%f", randValue),
+				fmt.Sprintf("%.2f", d.mean),
+				fmt.Sprintf("%.2f", d.sd),
+				fmt.Sprintf("%f", d.random),
 				"0",
 				"0",
 			})
 		} else {
-			distribution = append(distribution, []string{
-				edge,
+			table = append(table, []string{
+				name,
 				"равномерное",
 				"0",
 				"0",
 				"0",
-				results2[i+1][3],
-				results2[i+1][4],
+				fmt.Sprintf("%.2f", d.a),
+				fmt.Sprintf("%.2f", d.b),
 			})
 		}
 	}
-
-	return distribution
+	return table
 }
 
-func calculateExternalDistances(distanceMatrix [][]string) []float64 {
-	matrixSize := len(distanceMatrix) - 1 // Учитываем заголовки
+func calculateExternalDistances(distanceMatrix DistanceMatrix) []float64 {
+	matrixSize := len(distanceMatrix.Dist)
 	externalDistances := make([]float64, matrixSize)
-	for j := 1; j <= matrixSize; j++ {
+	for j := 0; j < matrixSize; j++ {
 		maxDist := 0.0
-		for i := 1; i <= matrixSize; i++ {
-			dist, _ := strconv.ParseFloat(distanceMatrix[i][j], 64)
-			if dist > maxDist {
+		for i := 0; i < matrixSize; i++ {
+			if dist := distanceMatrix.Dist[i][j]; dist > maxDist {
 				maxDist = dist
 			}
 		}
-		externalDistances[j-1] = maxDist
+		externalDistances[j] = maxDist
 	}
 	return externalDistances
 }
 
-func calculateInternalDistances(distanceMatrix [][]string) []float64 {
-	matrixSize := len(distanceMatrix) - 1 // Учитываем заголовки
+func calculateInternalDistances(distanceMatrix DistanceMatrix) []float64 {
+	matrixSize := len(distanceMatrix.Dist)
 	internalDistances := make([]float64, matrixSize)
-	for i := 1; i <= matrixSize; i++ {
+	for i := 0; i < matrixSize; i++ {
 		maxDist := 0.0
-		for j := 1; j <= matrixSize; j++ {
-			dist, _ := strconv.ParseFloat(distanceMatrix[i][j], 64)
-			if dist > maxDist {
+		for j := 0; j < matrixSize; j++ {
+			if dist := distanceMatrix.Dist[i][j]; dist > maxDist {
 				maxDist = dist
 			}
 		}
-		internalDistances[i-1] = maxDist
+		internalDistances[i] = maxDist
 	}
 	return internalDistances
 }
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestQuantileNonNegative(t *testing.T) {
+	// При большом разбросе a = E - √3·Ω < 0
+	tests := []edgeDistribution{
+		{edge: edge{"A", "B"}, mean: 2, sd: 5},
+		{edge: edge{"B", "C"}, mean: 2, sd: 5, normal: true},
+		{edge: edge{"A", "C"}, mean: 1, sd: 2},
+	}
+	for k := range tests {
+		if !tests[k].normal {
+			tests[k].a, tests[k].b = a(tests[k].mean, tests[k].sd), b(tests[k].mean, tests[k].sd)
+		}
+	}
+	for _, d := range tests {
+		for _, u := range []float64{0, 0.01, 0.2, 0.5, 0.99} {
+			if w := d.quantile(u); w < 0 {
+				t.Errorf("ребро %s-%s, u = %v: отрицательное время %v", d.origin, d.destination, u, w)
+			}
+		}
+	}
+
+	network := generateRandomNetwork([]string{"A", "B", "C"}, tests, []float64{0, 0, 0})
+	if backend := selectShortestPathBackend(network); backend == backendJohnson {
+		t.Errorf("сеть с обрезанными весами отправлена в %s", backend)
+	}
+	if _, err := allPairsShortestPaths(network, backendAuto); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// Результат расчёта сети с перекрытыми рёбрами
+type closureScenario struct {
+	closed  []int     // индексы перекрытых рёбер в распределении
+	values  []float64 // значение критерия удалённости по вершинам
+	optimal int       // -1, если сеть стала несвязной
+	worst   float64   // наибольшее время проезда до или от оптимальной вершины
+}
+
+// Функция для разбора сценариев перекрытий вида "1:3,5:6;2:4": сценарии
+// разделяются точкой с запятой, рёбра внутри сценария — запятой.
+// Направление ребра не важно.
+func parseClosures(spec string, distribution []edgeDistribution) ([][]int, error) {
+	var scenarios [][]int
+	for _, item := range strings.Split(spec, ";") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		var closed []int
+		for _, name := range strings.Split(item, ",") {
+			name = strings.TrimSpace(name)
+			k := slices.IndexFunc(distribution, func(d edgeDistribution) bool {
+				return name == d.origin+":"+d.destination || name == d.destination+":"+d.origin
+			})
+			if k < 0 {
+				return nil, fmt.Errorf("неизвестное ребро %q в сценарии перекрытий", name)
+			}
+			closed = append(closed, k)
+		}
+		scenarios = append(scenarios, closed)
+	}
+	return scenarios, nil
+}
+
+// Функция для расчёта сети средних времён проезда с перекрытыми рёбрами closed
+func evaluateClosure(points []string, distribution []edgeDistribution, closed []int) closureScenario {
+	weights := make([]float64, len(distribution))
+	for k, d := range distribution {
+		weights[k] = d.mean
+	}
+	for _, k := range closed {
+		weights[k] = math.Inf(1)
+	}
+
+	distMatrix := shortestDistances(networkFromWeights(points, distribution, weights))
+	extRad, intRad := calculateExternalDistances(distMatrix), calculateInternalDistances(distMatrix)
+	scenario := closureScenario{closed: closed, values: make([]float64, len(points)), worst: math.Inf(1)}
+	for i := range points {
+		scenario.values[i] = eccentricity.value(extRad[i], intRad[i])
+	}
+	scenario.optimal = optimalVertexIndex(intRad, extRad)
+	if scenario.optimal >= 0 {
+		scenario.worst = math.Max(extRad[scenario.optimal], intRad[scenario.optimal])
+	}
+	return scenario
+}
+
+// Функция для перечисления сценариев перекрытия каждого ребра по отдельности
+func singleClosures(points []string, distribution []edgeDistribution) []closureScenario {
+	scenarios := make([]closureScenario, len(distribution))
+	for k := range distribution {
+		scenarios[k] = evaluateClosure(points, distribution, []int{k})
+	}
+	return scenarios
+}
+
+// Функция для записи перекрытых рёбер сценария
+func formatClosure(distribution []edgeDistribution, closed []int) string {
+	if len(closed) == 0 {
+		return "без перекрытий"
+	}
+	names := make([]string, len(closed))
+	for i, k := range closed {
+		names[i] = distribution[k].origin + ":" + distribution[k].destination
+	}
+	return strings.Join(names, ", ")
+}
+
+// Функция для построения таблицы сценариев перекрытий относительно сети без перекрытий
+func closureTable(points []string, distribution []edgeDistribution, baseline closureScenario, scenarios []closureScenario) [][]string {
+	table := [][]string{{"Перекрытые рёбра", "Оптимальная вершина", "Значение критерия", "Наибольшее время проезда", "Изменение наибольшего времени"}}
+	for _, scenario := range append([]closureScenario{baseline}, scenarios...) {
+		if scenario.optimal < 0 {
+			table = append(table, []string{formatClosure(distribution, scenario.closed), "не определена (сеть несвязна)", "∞", "∞", "—"})
+			continue
+		}
+		optimal := points[scenario.optimal]
+		if baseline.optimal >= 0 && scenario.optimal != baseline.optimal {
+			optimal = fmt.Sprintf("%s → %s", points[baseline.optimal], optimal)
+		}
+		change := "—"
+		if baseline.optimal >= 0 {
+			change = fmt.Sprintf("%+.2f", scenario.worst-baseline.worst)
+		}
+		table = append(table, []string{
+			formatClosure(distribution, scenario.closed),
+			optimal,
+			formatDistance(scenario.values[scenario.optimal]),
+			formatDistance(scenario.worst),
+			change,
+		})
+	}
+	return table
+}
+
+// Функция для оценки устойчивости вершин к перекрытию любого одного ребра.
+// Вершина устойчива, если в каждом сценарии, где сеть осталась связной, её
+// критерий превышает лучший не более чем на tolerance процентов.
+// Устойчивые вершины выделяются.
+func closureRobustnessTable(points []string, scenarios []closureScenario, tolerance float64) reportTable {
+	table := [][]string{{"Вершина", "Оптимальна при перекрытиях", "Наибольшее значение критерия", "Наибольшее отставание от лучшей, %", "Устойчива"}}
+	connected := 0
+	for _, scenario := range scenarios {
+		if scenario.optimal >= 0 {
+			connected++
+		}
+	}
+	var robust []int
+	for i, vertex := range points {
+		wins, worst, regret := 0, 0.0, 0.0
+		for _, scenario := range scenarios {
+			if scenario.optimal < 0 {
+				continue
+			}
+			if scenario.optimal == i {
+				wins++
+			}
+			best := scenario.values[scenario.optimal]
+			worst = math.Max(worst, scenario.values[i])
+			if best > 0 {
+				regret = math.Max(regret, 100*(scenario.values[i]-best)/best)
+			}
+		}
+		row := []string{vertex, fmt.Sprintf("%d из %d", wins, connected), formatDistance(worst), fmt.Sprintf("%.1f", regret), "нет"}
+		if connected > 0 && regret <= tolerance {
+			row[4] = "да"
+			robust = append(robust, i)
+		}
+		table = append(table, row)
+	}
+
+	result := tableFromRows(table)
+	for _, i := range robust {
+		result.highlightRow(i)
+	}
+	return result
+}
+
+// Функция для формирования предупреждения о рёбрах, перекрытие которых
+// разрывает сеть. Пустая строка — таких рёбер нет.
+func closureWarning(distribution []edgeDistribution, scenarios []closureScenario) string {
+	var bridges []string
+	for _, scenario := range scenarios {
+		if scenario.optimal < 0 {
+			bridges = append(bridges, formatClosure(distribution, scenario.closed))
+		}
+	}
+	if len(bridges) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("перекрытие любого из рёбер %s делает часть вершин недостижимой; "+
+		"такие сценарии не учтены в оценке устойчивости вершин.", strings.Join(bridges, ", "))
+}
+
+// Функция для случайного отказа рёбер: каждое ребро независимо
+// перекрывается с вероятностью probability
+func failEdges(weights []float64, probability float64) {
+	for k := range weights {
+		if rand.Float64() < probability {
+			weights[k] = math.Inf(1)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Функция для поиска компонент связности без учёта направления дуг
+func connectedComponents(graph *Graph) [][]int {
+	n := graph.Len()
+
+	// Неориентированное представление: дуга u -> v даёт соседство в обе стороны
+	neighbours := make([][]int, n)
+	for u := 0; u < n; u++ {
+		for _, arc := range graph.Arcs(u) {
+			neighbours[u] = append(neighbours[u], arc.To)
+			neighbours[arc.To] = append(neighbours[arc.To], u)
+		}
+	}
+
+	component := make([]int, n)
+	for i := range component {
+		component[i] = -1
+	}
+	var components [][]int
+	for start := 0; start < n; start++ {
+		if component[start] >= 0 {
+			continue
+		}
+		id := len(components)
+		members := []int{start}
+		component[start] = id
+		for k := 0; k < len(members); k++ {
+			for _, v := range neighbours[members[k]] {
+				if component[v] < 0 {
+					component[v] = id
+					members = append(members, v)
+				}
+			}
+		}
+		components = append(components, members)
+	}
+	return components
+}
+
+// Функция для поиска компонент сильной связности (алгоритм Тарьяна)
+func stronglyConnectedComponents(graph *Graph) [][]int {
+	n := graph.Len()
+	index := make([]int, n)
+	lowLink := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+
+	var (
+		stack      []int
+		components [][]int
+		counter    int
+		visit      func(v int)
+	)
+	visit = func(v int) {
+		index[v], lowLink[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, arc := range graph.Arcs(v) {
+			if index[arc.To] < 0 {
+				visit(arc.To)
+				lowLink[v] = min(lowLink[v], lowLink[arc.To])
+			} else if onStack[arc.To] {
+				lowLink[v] = min(lowLink[v], index[arc.To])
+			}
+		}
+
+		if lowLink[v] == index[v] {
+			var members []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				members = append(members, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, members)
+		}
+	}
+
+	for v := 0; v < n; v++ {
+		if index[v] < 0 {
+			visit(v)
+		}
+	}
+	return components
+}
+
+// Функция для проверки, что из каждой вершины достижима любая другая
+func isStronglyConnected(graph *Graph) bool {
+	return graph.Len() > 0 && len(stronglyConnectedComponents(graph)) == 1
+}
+
+// Функция для построения таблицы компонент связности для отчёта
+func connectivityTable(graph *Graph) [][]string {
+	table := [][]string{{"Вид связности", "Компонента", "Вершины"}}
+	add := func(kind string, components [][]int) {
+		for i, members := range components {
+			ids := make([]string, len(members))
+			for k, v := range members {
+				ids[k] = graph.Vertices[v]
+			}
+			sortVertexIDs(ids)
+			table = append(table, []string{kind, fmt.Sprintf("%d", i+1), strings.Join(ids, ", ")})
+		}
+	}
+	add("Связность", connectedComponents(graph))
+	add("Сильная связность", stronglyConnectedComponents(graph))
+	return table
+}
+
+// Функция для формирования предупреждения о несвязной сети.
+// Пустая строка означает, что сеть сильно связна.
+func connectivityWarning(graph *Graph) string {
+	if isStronglyConnected(graph) {
+		return ""
+	}
+	weak, strong := len(connectedComponents(graph)), len(stronglyConnectedComponents(graph))
+	return fmt.Sprintf("сеть несвязна: %d компонент связности, %d компонент сильной связности. "+
+		"Часть вершин недостижима, их радиусы бесконечны, и оптимальная вершина не определена.", weak, strong)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+)
+
+// Наибольшее число точек каждой линии графика сходимости
+const convergencePoints = 500
+
+// Функция для вычисления текущей доли побед каждой вершины после каждой
+// итерации: shares[вершина][итерация]. Несвязные сети учитываются
+// в знаменателе, как и в итоговой доле.
+func runningShares(points []string, simulation placementSimulation) [][]float64 {
+	shares := make([][]float64, len(points))
+	wins := make([]int, len(points))
+	for i := range shares {
+		shares[i] = make([]float64, len(simulation.optimal))
+	}
+	for t, optimal := range simulation.optimal {
+		if optimal >= 0 {
+			wins[optimal]++
+		}
+		for i := range points {
+			shares[i][t] = float64(wins[i]) / float64(t+1)
+		}
+	}
+	return shares
+}
+
+// Функция для поиска итерации, начиная с которой текущая доля отличается
+// от итоговой не более чем на tolerance (итерации нумеруются с 1)
+func stabilizationIteration(shares []float64, tolerance float64) int {
+	if len(shares) == 0 {
+		return 0
+	}
+	final := shares[len(shares)-1]
+	for t := len(shares) - 1; t >= 0; t-- {
+		if math.Abs(shares[t]-final) > tolerance {
+			return t + 2 // номер первой итерации после последнего выхода из допуска
+		}
+	}
+	return 1
+}
+
+// Функция для построения графика сходимости текущих долей побед вершин.
+// Вершины, ни разу не оказавшиеся оптимальными, не рисуются.
+func createConvergenceChart(points []string, shares [][]float64, tolerance float64, title, filename string) {
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "Итерация"
+	p.Y.Label.Text = "Доля итераций, в которых вершина оптимальна"
+	p.Y.Min = 0
+	// Логарифмическая шкала показывает и начало моделирования, и хвост
+	p.X.Scale = plot.LogScale{}
+	p.X.Tick.Marker = plot.LogTicks{Prec: -1}
+
+	for i, vertexShares := range shares {
+		n := len(vertexShares)
+		if n == 0 || vertexShares[n-1] == 0 {
+			continue
+		}
+		// Точки равномерно по логарифмической шкале итераций
+		var line plotter.XYs
+		for k := 0; k <= convergencePoints; k++ {
+			t := int(math.Round(math.Pow(float64(n), float64(k)/convergencePoints)))
+			if len(line) > 0 && line[len(line)-1].X == float64(t) {
+				continue
+			}
+			line = append(line, plotter.XY{X: float64(t), Y: vertexShares[t-1]})
+		}
+		l, err := plotter.NewLine(line)
+		if err != nil {
+			log.Fatalf("Unable to create convergence line: %v", err)
+		}
+		l.Color = plotutil.Color(i)
+		l.Width = vg.Points(1.5)
+
+		// Коридор допуска вокруг итоговой доли
+		band, err := plotter.NewLine(plotter.XYs{{X: 1, Y: vertexShares[n-1] + tolerance}, {X: float64(n), Y: vertexShares[n-1] + tolerance}})
+		if err != nil {
+			log.Fatalf("Unable to create tolerance line: %v", err)
+		}
+		band.Color, band.Dashes = l.Color, []vg.Length{vg.Points(3), vg.Points(3)}
+		lower, err := plotter.NewLine(plotter.XYs{{X: 1, Y: vertexShares[n-1] - tolerance}, {X: float64(n), Y: vertexShares[n-1] - tolerance}})
+		if err != nil {
+			log.Fatalf("Unable to create tolerance line: %v", err)
+		}
+		lower.LineStyle = band.LineStyle
+		p.Add(l, band, lower)
+		p.Legend.Add(points[i], l)
+	}
+	p.Legend.Top = true
+
+	if err := savePlot(p, 8*vg.Inch, 4*vg.Inch, filename); err != nil {
+		log.Fatalf("Unable to save convergence chart: %v", err)
+	}
+}
+
+// Функция для формирования предупреждения, если какая-то доля стабилизировалась
+// только в последней десятой части итераций. Пустая строка — сходимость достигнута.
+func convergenceWarning(shares [][]float64, tolerance float64) string {
+	for _, vertexShares := range shares {
+		n := len(vertexShares)
+		if n > 0 && stabilizationIteration(vertexShares, tolerance) > n*9/10 {
+			return fmt.Sprintf("доли побед вершин вошли в допуск ±%g лишь в последней десятой части итераций; "+
+				"для надёжной оценки увеличьте число итераций (-iterations).", tolerance)
+		}
+	}
+	return ""
+}
+
+// Функция для построения таблицы сходимости: итоговая доля, её 95%
+// доверительный интервал и итерация, с которой доля держится в пределах
+// tolerance от итоговой. Вершина, стабилизировавшаяся последней, выделяется.
+func convergenceTable(points []string, shares [][]float64, tolerance float64) reportTable {
+	table := [][]string{{"Вершина", "Итоговая доля", "95% ДИ", fmt.Sprintf("Итерация стабилизации (±%g)", tolerance), "Доля итераций до стабилизации"}}
+	slowest, latest := -1, 0
+	for i, vertex := range points {
+		n := len(shares[i])
+		if n == 0 {
+			table = append(table, []string{vertex, "—", "—", "—", "—"})
+			continue
+		}
+		final := shares[i][n-1]
+		low, high := wilsonInterval(int(math.Round(final*float64(n))), n)
+		stable := stabilizationIteration(shares[i], tolerance)
+		if stable > latest {
+			slowest, latest = i, stable
+		}
+		table = append(table, []string{
+			vertex,
+			fmt.Sprintf("%.4f", final),
+			fmt.Sprintf("[%.4f; %.4f]", low, high),
+			strconv.Itoa(stable),
+			fmt.Sprintf("%.4f", float64(stable)/float64(n)),
+		})
+	}
+
+	result := tableFromRows(table)
+	if slowest >= 0 {
+		result.highlightRow(slowest)
+	}
+	return result
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Наибольшее число наборов пунктов, для которых в моделировании
+// оценивается вероятность полного покрытия
+const maxCoverageSets = 5000
+
+// Критерий максимального покрытия: спрос вершин, до которых от ближайшего
+// пункта нельзя доехать за coverTime. Минимизация непокрытого спроса
+// равносильна максимизации покрытого.
+func uncoveredDemandObjective(demand []float64, coverTime float64) placementObjective {
+	return func(cost [][]float64, facilities []int) float64 {
+		uncovered := 0.0
+		for v := range cost[0] {
+			if nearestFacilityCost(cost, facilities, v) > coverTime {
+				uncovered += demand[v]
+			}
+		}
+		return uncovered
+	}
+}
+
+// Функция для поиска вершин, не покрытых набором пунктов за coverTime
+func uncoveredVertices(distMatrix DistanceMatrix, facilities []int, coverTime float64) []int {
+	var uncovered []int
+	for v := range distMatrix.Vertices {
+		if nearestFacilityCost(distMatrix.Dist, facilities, v) > coverTime {
+			uncovered = append(uncovered, v)
+		}
+	}
+	return uncovered
+}
+
+// Функция для решения задачи о покрытии множества: наименьшее число пунктов,
+// при котором до каждой вершины можно доехать за coverTime. Возвращает nil,
+// если некоторые вершины недостижимы за coverTime ни из одного пункта.
+func solveSetCovering(distMatrix DistanceMatrix, coverTime float64) []int {
+	n := len(distMatrix.Vertices)
+	ones := make([]float64, n)
+	for i := range ones {
+		ones[i] = 1
+	}
+	objective := uncoveredDemandObjective(ones, coverTime)
+	for p := 1; p <= n; p++ {
+		if solution := solvePlacement(distMatrix.Dist, p, objective); solution.value == 0 {
+			return solution.facilities
+		}
+	}
+	return nil
+}
+
+// Функция для построения таблицы анализа покрытия на одной сети
+func coverageTable(distMatrix DistanceMatrix, demand []float64, coverTime float64, p int) [][]string {
+	total := 0.0
+	for _, d := range demand {
+		total += d
+	}
+
+	solution := solvePlacement(distMatrix.Dist, p, uncoveredDemandObjective(demand, coverTime))
+	uncovered := uncoveredVertices(distMatrix, solution.facilities, coverTime)
+	uncoveredNames := make([]string, len(uncovered))
+	for i, v := range uncovered {
+		uncoveredNames[i] = distMatrix.Vertices[v]
+	}
+	if len(uncoveredNames) == 0 {
+		uncoveredNames = []string{"нет"}
+	}
+
+	table := [][]string{
+		{"Название", "Результат"},
+		{"Норматив времени доезда", fmt.Sprintf("%g", coverTime)},
+		{fmt.Sprintf("Пункты максимального покрытия, p = %d", p), formatFacilities(distMatrix.Vertices, solution.facilities)},
+		{"Доля покрытого спроса", formatShare(total-solution.value, total)},
+		{"Непокрытые вершины", strings.Join(uncoveredNames, ", ")},
+	}
+	if cover := solveSetCovering(distMatrix, coverTime); cover != nil {
+		table = append(table,
+			[]string{"Наименьшее число пунктов для полного покрытия", fmt.Sprintf("%d", len(cover))},
+			[]string{"Пункты полного покрытия", formatFacilities(distMatrix.Vertices, cover)},
+		)
+	} else {
+		table = append(table, []string{"Наименьшее число пунктов для полного покрытия", "полное покрытие недостижимо"})
+	}
+	return table
+}
+
+// Статистика покрытия наборов пунктов по итерациям моделирования
+type coverageStats struct {
+	coverTime float64
+	full      map[string]int     // число сетей, где набор покрывает все вершины
+	share     map[string]float64 // сумма долей покрытого спроса
+}
+
+// Функция для учёта одной сети моделирования: для каждого набора из p
+// вершин проверяется полное покрытие. Если наборов больше maxCoverageSets,
+// учитывается только оптимальный по максимальному покрытию набор.
+func (stats *coverageStats) add(distMatrix DistanceMatrix, demand []float64, p int) {
+	total := 0.0
+	for _, d := range demand {
+		total += d
+	}
+	objective := uncoveredDemandObjective(demand, stats.coverTime)
+	record := func(facilities []int, uncovered float64) {
+		key := formatFacilities(distMatrix.Vertices, facilities)
+		if total > 0 {
+			stats.share[key] += (total - uncovered) / total
+		}
+		if uncovered == 0 {
+			stats.full[key]++
+		} else if _, ok := stats.full[key]; !ok {
+			stats.full[key] = 0
+		}
+	}
+
+	if binomial(len(distMatrix.Vertices), p) > maxCoverageSets {
+		solution := solvePlacement(distMatrix.Dist, p, objective)
+		record(solution.facilities, solution.value)
+		return
+	}
+	forEachCombination(len(distMatrix.Vertices), p, func(combination []int) {
+		record(combination, objective(distMatrix.Dist, combination))
+	})
+}
+
+// Функция для построения таблицы вероятностей полного покрытия по наборам пунктов
+func coverageProbabilityTable(stats coverageStats, iterations int) [][]string {
+	sets := make([]string, 0, len(stats.full))
+	for set := range stats.full {
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		a, b := stats.full[sets[i]], stats.full[sets[j]]
+		if a != b {
+			return a > b
+		}
+		if stats.share[sets[i]] != stats.share[sets[j]] {
+			return stats.share[sets[i]] > stats.share[sets[j]]
+		}
+		return sets[i] < sets[j]
+	})
+	if len(sets) > maxHistogramSets {
+		sets = sets[:maxHistogramSets]
+	}
+
+	table := [][]string{{"Набор пунктов", "Вероятность полного покрытия", "Средняя доля покрытого спроса"}}
+	for _, set := range sets {
+		// При нулевом суммарном спросе доли не накапливаются
+		share := "—"
+		if sum, ok := stats.share[set]; ok {
+			share = fmt.Sprintf("%.4f", sum/float64(iterations))
+		}
+		table = append(table, []string{
+			set,
+			fmt.Sprintf("%.4f", float64(stats.full[set])/float64(iterations)),
+			share,
+		})
+	}
+	return table
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Критерии удалённости вершины, по которым выбирается оптимальная вершина
+const (
+	eccentricityExternal = "ext"      // только внешний радиус
+	eccentricityInternal = "int"      // только внутренний радиус
+	eccentricityMax      = "max"      // наибольший из двух радиусов
+	eccentricitySum      = "sum"      // сумма радиусов
+	eccentricityWeighted = "weighted" // взвешенная сумма радиусов
+)
+
+var eccentricityNames = []string{eccentricityExternal, eccentricityInternal, eccentricityMax, eccentricitySum, eccentricityWeighted}
+
+// eccentricityCriterion — способ свести внешний и внутренний радиусы вершины к одному числу
+type eccentricityCriterion struct {
+	key    string
+	weight float64 // вес внешнего радиуса для взвешенной суммы
+}
+
+// Выбранный критерий удалённости; по умолчанию — сумма радиусов
+var eccentricity = eccentricityCriterion{key: eccentricitySum}
+
+// Функция для создания критерия удалённости по имени
+func newEccentricityCriterion(key string, weight float64) (eccentricityCriterion, error) {
+	if !slices.Contains(eccentricityNames, key) {
+		return eccentricityCriterion{}, fmt.Errorf("неизвестный критерий %q, доступны: %s", key, strings.Join(eccentricityNames, ", "))
+	}
+	if key == eccentricityWeighted && (weight < 0 || weight > 1) {
+		return eccentricityCriterion{}, fmt.Errorf("вес внешнего радиуса должен быть от 0 до 1, задано %g", weight)
+	}
+	return eccentricityCriterion{key: key, weight: weight}, nil
+}
+
+// value сводит внешний и внутренний радиусы вершины к значению критерия
+func (c eccentricityCriterion) value(external, internal float64) float64 {
+	switch c.key {
+	case eccentricityExternal:
+		return external
+	case eccentricityInternal:
+		return internal
+	case eccentricityMax:
+		return max(external, internal)
+	case eccentricityWeighted:
+		return c.weight*external + (1-c.weight)*internal
+	}
+	return external + internal
+}
+
+// title возвращает название критерия для заголовков отчёта
+func (c eccentricityCriterion) title() string {
+	switch c.key {
+	case eccentricityExternal:
+		return "Внешний радиус"
+	case eccentricityInternal:
+		return "Внутренний радиус"
+	case eccentricityMax:
+		return "Наибольший из радиусов"
+	case eccentricityWeighted:
+		return fmt.Sprintf("Взвешенная сумма радиусов (%.2f·внешний + %.2f·внутренний)", c.weight, 1-c.weight)
+	}
+	return "Сумма радиусов"
+}
+
+// Функция для вычисления значения критерия по всем смоделированным сетям:
+// values[вершина][сеть]
+func eccentricityValues(external, internal [][]float64) [][]float64 {
+	values := make([][]float64, len(external))
+	for i := range external {
+		values[i] = make([]float64, len(external[i]))
+		for s := range external[i] {
+			values[i][s] = eccentricity.value(external[i][s], internal[i][s])
+		}
+	}
+	return values
+}
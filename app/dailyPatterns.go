@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Число графиков в строке рисунков по рёбрам
+const patternColumns = 2
+
+// edgeSchedule — замеры одного ребра по датам сбора и временным срезам
+type edgeSchedule struct {
+	edge   string
+	dates  []string
+	slots  []float64   // минуты от полуночи, по возрастанию
+	values [][]float64 // values[дата][срез]; NaN — замера нет
+}
+
+// Функция для разбора замеров ребра по датам и срезам. dateHeader —
+// строка заголовка с датой в первом столбце каждого дня (остальные столбцы
+// дня пустые), slotHeader — строка с временем среза каждого столбца.
+func buildEdgeSchedules(dateHeader, slotHeader []string, data [][]string) ([]edgeSchedule, error) {
+	columnDates := make([]string, len(slotHeader))
+	columnSlots := make([]float64, len(slotHeader))
+	var dates []string
+	var slots []float64
+	date := ""
+	for j := 1; j < len(slotHeader); j++ {
+		if j < len(dateHeader) && strings.TrimSpace(dateHeader[j]) != "" {
+			date = strings.TrimSpace(dateHeader[j])
+		}
+		slot, err := parseSlot(slotHeader[j])
+		if err != nil {
+			return nil, err
+		}
+		columnDates[j], columnSlots[j] = date, slot
+		if !slices.Contains(dates, date) {
+			dates = append(dates, date)
+		}
+		if !slices.Contains(slots, slot) {
+			slots = append(slots, slot)
+		}
+	}
+	slices.Sort(dates)
+	slices.Sort(slots)
+
+	schedules := make([]edgeSchedule, len(data))
+	for k, record := range data {
+		schedule := edgeSchedule{edge: record[0], dates: dates, slots: slots, values: make([][]float64, len(dates))}
+		for d := range dates {
+			schedule.values[d] = make([]float64, len(slots))
+			for s := range slots {
+				schedule.values[d][s] = math.NaN()
+			}
+		}
+		for j := 1; j < len(record) && j < len(slotHeader); j++ {
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[j]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("ребро %s: некорректный замер %q", record[0], record[j])
+			}
+			d, _ := slices.BinarySearch(dates, columnDates[j])
+			s, _ := slices.BinarySearch(slots, columnSlots[j])
+			schedule.values[d][s] = value
+		}
+		schedules[k] = schedule
+	}
+	return schedules, nil
+}
+
+// Dims, Z, X и Y реализуют plotter.GridXYZ: столбцы — срезы, строки — даты
+func (s edgeSchedule) Dims() (int, int)   { return len(s.slots), len(s.dates) }
+func (s edgeSchedule) Z(c, r int) float64 { return s.values[r][c] }
+func (s edgeSchedule) X(c int) float64    { return float64(c) }
+func (s edgeSchedule) Y(r int) float64    { return float64(r) }
+
+// Функция для вычисления среднего времени проезда в срезе slot по всем датам
+// и 95% доверительного интервала по распределению Стьюдента
+func (s edgeSchedule) slotMean(slot int) (mean, low, high float64) {
+	var samples []float64
+	for d := range s.dates {
+		if v := s.values[d][slot]; !math.IsNaN(v) {
+			samples = append(samples, v)
+		}
+	}
+	if len(samples) == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	mean = meanOf(samples)
+	if len(samples) < 2 {
+		return mean, mean, mean
+	}
+	variance := 0.0
+	for _, v := range samples {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(samples) - 1)
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(len(samples) - 1)}.Quantile(0.975)
+	half := t * math.Sqrt(variance/float64(len(samples)))
+	return mean, mean - half, mean + half
+}
+
+// gradientPalette — палитра тепловой карты в цветах карты вершин
+type gradientPalette []color.Color
+
+func (p gradientPalette) Colors() []color.Color { return p }
+
+func newGradientPalette(n int) gradientPalette {
+	colors := make(gradientPalette, n)
+	for i := range colors {
+		colors[i] = probabilityColor(float64(i), float64(n-1))
+	}
+	return colors
+}
+
+// Функция для построения тепловой карты замеров ребра: даты по вертикали,
+// срезы по горизонтали, в ячейках — время проезда
+func scheduleHeatMap(schedule edgeSchedule) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = "Ребро " + schedule.edge
+	p.X.Label.Text = "Время среза"
+	p.Y.Label.Text = "Дата"
+
+	heatMap := plotter.NewHeatMap(schedule, newGradientPalette(32))
+	if heatMap.Max == heatMap.Min {
+		heatMap.Max = heatMap.Min + 1
+	}
+	heatMap.NaN = color.White
+	p.Add(heatMap)
+
+	var cells plotter.XYs
+	var labels []string
+	var styles []text.Style
+	for d := range schedule.dates {
+		for s := range schedule.slots {
+			value := schedule.values[d][s]
+			if math.IsNaN(value) {
+				continue
+			}
+			style := text.Style{Color: color.Black, Font: plot.DefaultFont, Handler: plot.DefaultTextHandler, XAlign: draw.XCenter, YAlign: draw.YCenter}
+			style.Font.Size = vg.Points(8)
+			// Светлый текст на тёмных ячейках
+			if (value-heatMap.Min)/(heatMap.Max-heatMap.Min) > 0.6 {
+				style.Color = color.White
+			}
+			cells = append(cells, plotter.XY{X: float64(s), Y: float64(d)})
+			labels = append(labels, strconv.FormatFloat(value, 'f', -1, 64))
+			styles = append(styles, style)
+		}
+	}
+	if len(cells) > 0 {
+		values, err := plotter.NewLabels(plotter.XYLabels{XYs: cells, Labels: labels})
+		if err != nil {
+			return nil, err
+		}
+		values.TextStyle = styles
+		p.Add(values)
+	}
+
+	slotNames := make([]string, len(schedule.slots))
+	for s, slot := range schedule.slots {
+		slotNames[s] = formatSlot(slot)
+	}
+	p.NominalX(slotNames...)
+	p.NominalY(schedule.dates...)
+	return p, nil
+}
+
+// Функция для построения графика среднего времени проезда по срезам
+// с 95% доверительной полосой
+func scheduleTrend(schedule edgeSchedule) (*plot.Plot, error) {
+	var means, lows, highs plotter.XYs
+	for s, slot := range schedule.slots {
+		mean, low, high := schedule.slotMean(s)
+		if math.IsNaN(mean) {
+			continue
+		}
+		hour := slot / 60
+		means = append(means, plotter.XY{X: hour, Y: mean})
+		lows = append(lows, plotter.XY{X: hour, Y: low})
+		highs = append(highs, plotter.XY{X: hour, Y: high})
+	}
+
+	p := plot.New()
+	p.Title.Text = "Ребро " + schedule.edge
+	p.X.Label.Text = "Час отправления"
+	p.Y.Label.Text = "Среднее время проезда"
+	if len(means) == 0 {
+		return p, nil
+	}
+
+	// Полоса: нижняя граница слева направо, верхняя — справа налево
+	band := slices.Clone(lows)
+	for i := len(highs) - 1; i >= 0; i-- {
+		band = append(band, highs[i])
+	}
+	polygon, err := plotter.NewPolygon(band)
+	if err != nil {
+		return nil, err
+	}
+	polygon.Color = color.NRGBA{R: 70, G: 130, B: 180, A: 60}
+	polygon.LineStyle.Width = 0
+	line, points, err := plotter.NewLinePoints(means)
+	if err != nil {
+		return nil, err
+	}
+	line.Color, line.Width = color.RGBA{R: 70, G: 130, B: 180, A: 255}, vg.Points(1.5)
+	points.GlyphStyle = draw.GlyphStyle{Color: line.Color, Radius: vg.Points(2.5), Shape: draw.CircleGlyph{}}
+	p.Add(polygon, line, points)
+	p.Legend.Add("среднее", line, points)
+	p.Legend.Add("95% доверительный интервал", polygon)
+	p.Legend.Top = true
+	p.Y.Min = math.Max(p.Y.Min-(p.Y.Max-p.Y.Min)*0.1, 0)
+	p.Y.Max += (p.Y.Max - p.Y.Min) * 0.5
+	var ticks []plot.Tick
+	for _, slot := range schedule.slots {
+		ticks = append(ticks, plot.Tick{Value: slot / 60, Label: formatSlot(slot)})
+	}
+	p.X.Tick.Marker = plot.ConstantTicks(ticks)
+	return p, nil
+}
+
+// Функция для сборки графиков рёбер в сетку по patternColumns в строке;
+// пустые места последней строки заполняются пустыми графиками
+func scheduleGrid(schedules []edgeSchedule, build func(edgeSchedule) (*plot.Plot, error)) ([][]*plot.Plot, error) {
+	var grid [][]*plot.Plot
+	for k, schedule := range schedules {
+		p, err := build(schedule)
+		if err != nil {
+			return nil, err
+		}
+		if k%patternColumns == 0 {
+			grid = append(grid, nil)
+		}
+		grid[len(grid)-1] = append(grid[len(grid)-1], p)
+	}
+	if len(grid) > 0 {
+		for last := grid[len(grid)-1]; len(last) < patternColumns; last = grid[len(grid)-1] {
+			blank := plot.New()
+			blank.HideAxes()
+			grid[len(grid)-1] = append(last, blank)
+		}
+	}
+	return grid, nil
+}
+
+// Функция для построения тепловых карт и графиков средних по срезам для всех рёбер
+func createSchedulePlots(schedules []edgeSchedule, heatMapFile, trendFile string) error {
+	if len(schedules) == 0 {
+		return fmt.Errorf("нет рёбер")
+	}
+	heatMaps, err := scheduleGrid(schedules, scheduleHeatMap)
+	if err != nil {
+		return err
+	}
+	rowHeight := vg.Length(math.Max(3, 0.3*float64(len(schedules[0].dates)))) * vg.Inch
+	if err := savePlotGrid(heatMaps, 14*vg.Inch, rowHeight*vg.Length(len(heatMaps)), heatMapFile); err != nil {
+		return err
+	}
+	trends, err := scheduleGrid(schedules, scheduleTrend)
+	if err != nil {
+		return err
+	}
+	return savePlotGrid(trends, 14*vg.Inch, 3*vg.Inch*vg.Length(len(trends)), trendFile)
+}
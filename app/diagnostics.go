@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Кандидат в распределения времени проезда по ребру, проверяемый
+// критерием хи-квадрат в таблицах "Результаты 1" и "Результаты 2"
+type candidateDistribution struct {
+	name     string
+	color    color.Color
+	pdf      func(float64) float64
+	cdf      func(float64) float64
+	quantile func(float64) float64
+}
+
+// Функция для построения нормального и равномерного кандидатов с теми же
+// параметрами, что и при проверке по хи-квадрат
+func edgeCandidates(samples []int) []candidateDistribution {
+	avg, omega := AVG(samples), Omega(samples)
+	low := a(avg, omega)
+	high := b(avg, low)
+	normal := distuv.Normal{Mu: avg, Sigma: math.Max(omega, 1e-9)}
+	uniform := distuv.Uniform{Min: low, Max: math.Max(high, low+1e-9)}
+	return []candidateDistribution{
+		{
+			name:     fmt.Sprintf("нормальное (µ=%.2f, σ=%.2f)", avg, omega),
+			color:    color.RGBA{R: 205, G: 92, B: 92, A: 255},
+			pdf:      normal.Prob,
+			cdf:      normal.CDF,
+			quantile: normal.Quantile,
+		},
+		{
+			name:     fmt.Sprintf("равномерное (a=%.2f, b=%.2f)", low, high),
+			color:    color.RGBA{R: 46, G: 139, B: 87, A: 255},
+			pdf:      uniform.Prob,
+			cdf:      uniform.CDF,
+			quantile: uniform.Quantile,
+		},
+	}
+}
+
+// Функция для получения замеров ребра из строки данных "ребро,замер1,..."
+func edgeSamples(record []string) []int {
+	var samples []int
+	for _, field := range record[1:] {
+		if value, err := strconv.Atoi(strings.TrimSpace(field)); err == nil {
+			samples = append(samples, value)
+		}
+	}
+	return samples
+}
+
+// Функция для построения гистограммы замеров с плотностями кандидатов
+func diagnosticHistogram(samples []int, candidates []candidateDistribution) (*plot.Plot, error) {
+	values := make(plotter.Values, len(samples))
+	for i, v := range samples {
+		values[i] = float64(v)
+	}
+	p := plot.New()
+	p.Title.Text = "Гистограмма и плотности"
+	p.X.Label.Text = "Время проезда"
+	p.Y.Label.Text = "Плотность"
+
+	bins := int(math.Max(5, math.Ceil(math.Sqrt(float64(len(samples))))))
+	hist, err := plotter.NewHist(values, bins)
+	if err != nil {
+		return nil, err
+	}
+	hist.Normalize(1)
+	hist.FillColor = color.RGBA{R: 176, G: 196, B: 222, A: 255}
+	p.Add(hist)
+
+	xMin, xMax := sampleRange(samples)
+	for _, candidate := range candidates {
+		f := plotter.NewFunction(candidate.pdf)
+		f.XMin, f.XMax, f.Samples = xMin, xMax, 200
+		f.Color, f.Width = candidate.color, vg.Points(1.5)
+		p.Add(f)
+		p.Legend.Add(candidate.name, f)
+	}
+	p.Legend.Top = true
+	// Место над столбцами для легенды
+	p.X.Min, p.X.Max = xMin, xMax
+	p.Y.Max *= 1.3
+	return p, nil
+}
+
+// Функция для построения эмпирической и теоретических функций распределения
+func diagnosticCDF(samples []int, candidates []candidateDistribution) (*plot.Plot, error) {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	n := float64(len(sorted))
+
+	// Ступенчатая эмпирическая функция распределения
+	var steps plotter.XYs
+	for i, v := range sorted {
+		steps = append(steps, plotter.XY{X: float64(v), Y: float64(i) / n}, plotter.XY{X: float64(v), Y: float64(i+1) / n})
+	}
+	p := plot.New()
+	p.Title.Text = "Функции распределения"
+	p.X.Label.Text = "Время проезда"
+	p.Y.Label.Text = "F(x)"
+	empirical, err := plotter.NewLine(steps)
+	if err != nil {
+		return nil, err
+	}
+	empirical.Width = vg.Points(1.5)
+	p.Add(empirical)
+	p.Legend.Add("эмпирическая", empirical)
+
+	xMin, xMax := sampleRange(samples)
+	for _, candidate := range candidates {
+		f := plotter.NewFunction(candidate.cdf)
+		f.XMin, f.XMax, f.Samples = xMin, xMax, 200
+		f.Color, f.Width = candidate.color, vg.Points(1.5)
+		p.Add(f)
+	}
+	p.Legend.Top, p.Legend.Left = true, true
+	p.X.Min, p.X.Max = xMin, xMax
+	p.Y.Max = 1.15
+	return p, nil
+}
+
+// Функция для построения графика квантиль — квантиль: теоретические
+// квантили кандидата по оси X, упорядоченные замеры по оси Y
+func diagnosticQQ(samples []int, candidate candidateDistribution) (*plot.Plot, error) {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	n := float64(len(sorted))
+	points := make(plotter.XYs, len(sorted))
+	for i, v := range sorted {
+		points[i] = plotter.XY{X: candidate.quantile((float64(i) + 0.5) / n), Y: float64(v)}
+	}
+
+	p := plot.New()
+	p.Title.Text = "Q–Q: " + strings.SplitN(candidate.name, " ", 2)[0]
+	p.X.Label.Text = "Теоретический квантиль"
+	p.Y.Label.Text = "Замер"
+	scatter, err := plotter.NewScatter(points)
+	if err != nil {
+		return nil, err
+	}
+	scatter.GlyphStyle = draw.GlyphStyle{Color: candidate.color, Radius: vg.Points(2), Shape: draw.CircleGlyph{}}
+
+	xMin, xMax, yMin, yMax := plotter.XYRange(points)
+	low, high := math.Min(xMin, yMin), math.Max(xMax, yMax)
+	diagonal, err := plotter.NewLine(plotter.XYs{{X: low, Y: low}, {X: high, Y: high}})
+	if err != nil {
+		return nil, err
+	}
+	diagonal.Dashes = []vg.Length{vg.Points(4), vg.Points(3)}
+	p.Add(diagonal, scatter)
+	return p, nil
+}
+
+// Функция для получения границ замеров с полями для графиков плотности
+func sampleRange(samples []int) (float64, float64) {
+	low, high := slices.Min(samples), slices.Max(samples)
+	pad := math.Max(float64(high-low)*0.1, 1)
+	return float64(low) - pad, float64(high) + pad
+}
+
+// Функция для построения диагностических графиков ребра: гистограмма
+// с плотностями кандидатов, функции распределения и графики Q–Q
+// для каждого кандидата, собранные в один рисунок
+func createEdgeDiagnostics(record []string, filename string) error {
+	samples := edgeSamples(record)
+	if len(samples) < 2 {
+		return fmt.Errorf("ребро %s: недостаточно замеров", record[0])
+	}
+	candidates := edgeCandidates(samples)
+
+	histogram, err := diagnosticHistogram(samples, candidates)
+	if err != nil {
+		return err
+	}
+	cdf, err := diagnosticCDF(samples, candidates)
+	if err != nil {
+		return err
+	}
+	row := []*plot.Plot{histogram, cdf}
+	for _, candidate := range candidates {
+		qq, err := diagnosticQQ(samples, candidate)
+		if err != nil {
+			return err
+		}
+		row = append(row, qq)
+	}
+	return savePlotGrid([][]*plot.Plot{row}, 16*vg.Inch, 4*vg.Inch, filename)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Arc — дуга графа: индекс конечной вершины и время проезда
+type Arc struct {
+	To     int
+	Weight float64
+}
+
+// Graph — взвешенный граф дорожной сети. Вершины адресуются индексами
+// в Vertices, отсутствие ребра хранится явно (дуги просто нет),
+// поэтому ребро нулевой длины допустимо.
+type Graph struct {
+	Vertices []string
+	index    map[string]int
+	adj      [][]Arc
+}
+
+// DistanceMatrix — матрица кратчайших расстояний между вершинами.
+// Недостижимая вершина обозначается +Inf. Pred[i][j] — предпоследняя
+// вершина кратчайшего пути из i в j (-1, если пути нет или i == j).
+type DistanceMatrix struct {
+	Vertices []string
+	Dist     [][]float64
+	Pred     [][]int
+}
+
+func NewGraph(vertices []string) *Graph {
+	g := &Graph{
+		Vertices: vertices,
+		index:    make(map[string]int, len(vertices)),
+		adj:      make([][]Arc, len(vertices)),
+	}
+	for i, v := range vertices {
+		g.index[v] = i
+	}
+	return g
+}
+
+// Len возвращает число вершин
+func (g *Graph) Len() int {
+	return len(g.Vertices)
+}
+
+// Index возвращает индекс вершины по её идентификатору
+func (g *Graph) Index(id string) (int, bool) {
+	i, ok := g.index[id]
+	return i, ok
+}
+
+// SetEdge добавляет дугу from -> to или заменяет её вес
+func (g *Graph) SetEdge(from, to int, weight float64) {
+	for k := range g.adj[from] {
+		if g.adj[from][k].To == to {
+			g.adj[from][k].Weight = weight
+			return
+		}
+	}
+	g.adj[from] = append(g.adj[from], Arc{To: to, Weight: weight})
+}
+
+// SetUndirectedEdge добавляет ребро в обоих направлениях
+func (g *Graph) SetUndirectedEdge(i, j int, weight float64) {
+	g.SetEdge(i, j, weight)
+	g.SetEdge(j, i, weight)
+}
+
+// Edge возвращает вес дуги from -> to и признак её наличия
+func (g *Graph) Edge(from, to int) (float64, bool) {
+	for _, arc := range g.adj[from] {
+		if arc.To == to {
+			return arc.Weight, true
+		}
+	}
+	return 0, false
+}
+
+// Arcs возвращает исходящие дуги вершины
+func (g *Graph) Arcs(from int) []Arc {
+	return g.adj[from]
+}
+
+// Table переводит граф в матрицу смежности для отчёта
+func (g *Graph) Table() [][]string {
+	n := g.Len()
+	table := make([][]string, n+1)
+	table[0] = append([]string{""}, g.Vertices...)
+	for i := 0; i < n; i++ {
+		table[i+1] = make([]string, n+1)
+		table[i+1][0] = g.Vertices[i]
+		for j := 0; j < n; j++ {
+			switch w, ok := g.Edge(i, j); {
+			case ok:
+				table[i+1][j+1] = fmt.Sprintf("%.2f", w)
+			case i == j:
+				table[i+1][j+1] = "0"
+			default:
+				table[i+1][j+1] = "—"
+			}
+		}
+	}
+	return table
+}
+
+func newDistanceMatrix(vertices []string) DistanceMatrix {
+	dist := make([][]float64, len(vertices))
+	pred := make([][]int, len(vertices))
+	for i := range dist {
+		dist[i] = make([]float64, len(vertices))
+		pred[i] = make([]int, len(vertices))
+	}
+	return DistanceMatrix{Vertices: vertices, Dist: dist, Pred: pred}
+}
+
+// Path восстанавливает кратчайший путь из from в to как последовательность
+// индексов вершин. Для недостижимой вершины возвращает nil.
+func (m DistanceMatrix) Path(from, to int) []int {
+	if math.IsInf(m.Dist[from][to], 1) {
+		return nil
+	}
+	path := []int{to}
+	for v := to; v != from; {
+		v = m.Pred[from][v]
+		if v < 0 || len(path) > len(m.Vertices) {
+			return nil
+		}
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Table переводит матрицу расстояний в таблицу для отчёта
+func (m DistanceMatrix) Table() [][]string {
+	n := len(m.Vertices)
+	table := make([][]string, n+1)
+	table[0] = append([]string{""}, m.Vertices...)
+	for i := 0; i < n; i++ {
+		table[i+1] = make([]string, n+1)
+		table[i+1][0] = m.Vertices[i]
+		for j := 0; j < n; j++ {
+			table[i+1][j+1] = formatDistance(m.Dist[i][j])
+		}
+	}
+	return table
+}
+
+// Функция для форматирования расстояния с учётом недостижимости
+func formatDistance(dist float64) string {
+	if math.IsInf(dist, 1) {
+		return "∞"
+	}
+	return fmt.Sprintf("%.2f", dist)
+}
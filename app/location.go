@@ -0,0 +1,385 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Полный перебор наборов пунктов выполняется, пока их число не превышает
+// этого порога; для больших сетей используется эвристика
+const exactPlacementLimit = 200000
+
+// placementObjective — критерий качества набора пунктов обслуживания.
+// cost[c][v] — время проезда от кандидата c до вершины v, меньше — лучше.
+type placementObjective func(cost [][]float64, facilities []int) float64
+
+// Взвешенный p-центр: наибольшее произведение спроса вершины на время
+// проезда до неё от ближайшего пункта
+func weightedPCenterObjective(demand []float64) placementObjective {
+	return func(cost [][]float64, facilities []int) float64 {
+		worst := 0.0
+		for v := range cost[0] {
+			if weighted := demand[v] * nearestFacilityCost(cost, facilities, v); weighted > worst {
+				worst = weighted
+			}
+		}
+		return worst
+	}
+}
+
+// Взвешенная p-медиана: суммарное время проезда до всех вершин
+// от ближайших пунктов, взвешенное спросом
+func weightedPMedianObjective(demand []float64) placementObjective {
+	return func(cost [][]float64, facilities []int) float64 {
+		total := 0.0
+		for v := range cost[0] {
+			if demand[v] == 0 {
+				continue
+			}
+			total += demand[v] * nearestFacilityCost(cost, facilities, v)
+		}
+		return total
+	}
+}
+
+// Функция для нахождения времени проезда до вершины v от ближайшего пункта
+func nearestFacilityCost(cost [][]float64, facilities []int, v int) float64 {
+	nearest := math.Inf(1)
+	for _, f := range facilities {
+		if cost[f][v] < nearest {
+			nearest = cost[f][v]
+		}
+	}
+	return nearest
+}
+
+// Результат решения задачи размещения
+type placement struct {
+	facilities []int
+	value      float64
+	exact      bool // найден полным перебором
+}
+
+// Функция для решения задачи размещения p пунктов среди кандидатов (строк cost):
+// полный перебор для небольших задач, иначе жадный выбор с улучшением заменами
+func solvePlacement(cost [][]float64, p int, objective placementObjective) placement {
+	candidates := len(cost)
+	p = min(p, candidates)
+	if binomial(candidates, p) <= exactPlacementLimit {
+		return exactPlacement(cost, p, objective)
+	}
+	return interchangePlacement(cost, greedyPlacement(cost, p, objective), objective)
+}
+
+// Функция для полного перебора всех наборов из p кандидатов
+func exactPlacement(cost [][]float64, p int, objective placementObjective) placement {
+	best := placement{value: math.Inf(1), exact: true}
+	forEachCombination(len(cost), p, func(combination []int) {
+		if value := objective(cost, combination); value < best.value || best.facilities == nil {
+			best.value = value
+			best.facilities = slices.Clone(combination)
+		}
+	})
+	return best
+}
+
+// Функция для обхода всех сочетаний из n по p в лексикографическом порядке.
+// Срез combination переиспользуется между вызовами visit.
+func forEachCombination(n, p int, visit func(combination []int)) {
+	if p > n || p < 1 {
+		return
+	}
+	combination := make([]int, p)
+	for i := range combination {
+		combination[i] = i
+	}
+	for {
+		visit(combination)
+
+		i := p - 1
+		for i >= 0 && combination[i] == n-p+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		combination[i]++
+		for j := i + 1; j < p; j++ {
+			combination[j] = combination[j-1] + 1
+		}
+	}
+}
+
+// Функция для жадного выбора: пункты добавляются по одному, каждый раз
+// тот, что сильнее всего улучшает критерий
+func greedyPlacement(cost [][]float64, p int, objective placementObjective) placement {
+	var facilities []int
+	value := math.Inf(1)
+	for len(facilities) < p {
+		bestCandidate, bestValue := -1, math.Inf(1)
+		for c := range cost {
+			if slices.Contains(facilities, c) {
+				continue
+			}
+			if v := objective(cost, append(facilities, c)); v < bestValue || bestCandidate < 0 {
+				bestCandidate, bestValue = c, v
+			}
+		}
+		facilities = append(facilities, bestCandidate)
+		value = bestValue
+	}
+	return placement{facilities: facilities, value: value}
+}
+
+// Функция для улучшения размещения заменами (Тейц–Барт): пункт меняется
+// на кандидата вне набора, пока это уменьшает критерий
+func interchangePlacement(cost [][]float64, start placement, objective placementObjective) placement {
+	current := placement{facilities: slices.Clone(start.facilities), value: start.value}
+	for improved := true; improved; {
+		improved = false
+		for i := range current.facilities {
+			for c := range cost {
+				if slices.Contains(current.facilities, c) {
+					continue
+				}
+				previous := current.facilities[i]
+				current.facilities[i] = c
+				if value := objective(cost, current.facilities); value < current.value {
+					current.value = value
+					improved = true
+					continue
+				}
+				current.facilities[i] = previous
+			}
+		}
+	}
+	slices.Sort(current.facilities)
+	return current
+}
+
+// Функция для вычисления биномиального коэффициента с насыщением
+func binomial(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	k = min(k, n-k)
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+		if result > exactPlacementLimit*10 {
+			return result
+		}
+	}
+	return result
+}
+
+// Функция для разбора спроса по вершинам. spec — либо путь к CSV-файлу
+// со строками "вершина,спрос", либо список вида "1=3200,2=1500".
+// Спрос должен быть задан для каждой вершины; пустой spec означает
+// одинаковый спрос 1 у всех вершин.
+func parseDemand(spec string, vertices []string) ([]float64, error) {
+	demand := make([]float64, len(vertices))
+	if spec == "" {
+		for i := range demand {
+			demand[i] = 1
+		}
+		return demand, nil
+	}
+
+	var pairs [][]string
+	file, err := os.Open(spec)
+	switch {
+	case err == nil:
+		defer file.Close()
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = 2
+		pairs, err = reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("файл спроса %s: %v", spec, err)
+		}
+	case strings.ContainsAny(spec, `/\`) || strings.HasSuffix(strings.ToLower(spec), ".csv"):
+		// Похоже на путь к файлу: ошибка открытия понятнее ошибки разбора списка
+		return nil, fmt.Errorf("файл спроса: %w", err)
+	default:
+		for _, item := range strings.Split(spec, ",") {
+			pair := strings.SplitN(strings.TrimSpace(item), "=", 2)
+			if len(pair) != 2 {
+				return nil, fmt.Errorf("некорректный элемент спроса %q, ожидается вершина=спрос", item)
+			}
+			pairs = append(pairs, pair)
+		}
+	}
+
+	index := make(map[string]int, len(vertices))
+	for i, v := range vertices {
+		index[v] = i
+	}
+	given := make([]bool, len(vertices))
+	for _, pair := range pairs {
+		i, ok := index[strings.TrimSpace(pair[0])]
+		if !ok {
+			return nil, fmt.Errorf("спрос задан для неизвестной вершины %q", pair[0])
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(pair[1]), 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("некорректный спрос %q для вершины %s", pair[1], pair[0])
+		}
+		demand[i], given[i] = value, true
+	}
+
+	var missing []string
+	for i, ok := range given {
+		if !ok {
+			missing = append(missing, vertices[i])
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("спрос не задан для вершин %s", strings.Join(missing, ", "))
+	}
+	return demand, nil
+}
+
+// Функция для построения таблицы спроса по вершинам
+func demandTable(vertices []string, demand []float64) [][]string {
+	total := 0.0
+	for _, d := range demand {
+		total += d
+	}
+	table := [][]string{{"Вершина", "Спрос", "Доля"}}
+	for i, v := range vertices {
+		table = append(table, []string{v, strconv.FormatFloat(demand[i], 'f', -1, 64), formatShare(demand[i], total)})
+	}
+	return table
+}
+
+// Функция для записи доли part от total; при нулевом total доля не определена
+func formatShare(part, total float64) string {
+	if total == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%.4f", part/total)
+}
+
+// Функция для записи набора пунктов в виде "{2, 5}"
+func formatFacilities(vertices []string, facilities []int) string {
+	names := make([]string, len(facilities))
+	for i, f := range facilities {
+		names[i] = vertices[f]
+	}
+	return "{" + strings.Join(names, ", ") + "}"
+}
+
+// Критерии выбора размещения
+const (
+	objectiveRadius  = "radius"  // одна вершина с минимальным значением критерия удалённости
+	objectivePCenter = "pcenter" // p пунктов, минимум наибольшего (взвешенного) времени проезда
+	objectivePMedian = "pmedian" // p пунктов, минимум суммарного взвешенного времени проезда
+	objectiveCover   = "cover"   // p пунктов, максимум спроса, доступного за заданное время
+)
+
+var objectiveNames = []string{objectiveRadius, objectivePCenter, objectivePMedian, objectiveCover}
+
+// placementCriterion — критерий размещения, решаемый на каждой сети моделирования
+type placementCriterion struct {
+	key        string
+	title      string // название для заголовков отчёта
+	valueLabel string // смысл значения критерия
+	facilities int
+	solve      func(distMatrix DistanceMatrix) placement
+}
+
+// Функция для создания критерия размещения по имени. demand — спрос
+// по вершинам в порядке peaks, используется взвешенными критериями,
+// coverTime — норматив времени доезда для критерия покрытия.
+func newPlacementCriterion(key string, p int, demand []float64, coverTime float64) (placementCriterion, error) {
+	switch key {
+	case objectiveRadius:
+		return placementCriterion{
+			key:        key,
+			title:      "минимум: " + strings.ToLower(eccentricity.title()),
+			valueLabel: eccentricity.title(),
+			facilities: 1,
+			solve: func(distMatrix DistanceMatrix) placement {
+				extRad, intRad := calculateExternalDistances(distMatrix), calculateInternalDistances(distMatrix)
+				optimal := optimalVertexIndex(intRad, extRad)
+				if optimal < 0 {
+					return placement{value: math.Inf(1), exact: true}
+				}
+				return placement{facilities: []int{optimal}, value: eccentricity.value(extRad[optimal], intRad[optimal]), exact: true}
+			},
+		}, nil
+	case objectiveCover:
+		if coverTime <= 0 {
+			return placementCriterion{}, fmt.Errorf("для критерия %s нужно задать норматив времени -cover-time", key)
+		}
+		if p < 1 {
+			return placementCriterion{}, fmt.Errorf("для критерия %s нужно хотя бы один пункт, задано %d", key, p)
+		}
+		objective := uncoveredDemandObjective(demand, coverTime)
+		return placementCriterion{
+			key:        key,
+			title:      fmt.Sprintf("максимальное покрытие за %g мин, p = %d", coverTime, p),
+			valueLabel: "Непокрытый спрос",
+			facilities: p,
+			solve: func(distMatrix DistanceMatrix) placement {
+				return solvePlacement(distMatrix.Dist, p, objective)
+			},
+		}, nil
+	case objectivePCenter, objectivePMedian:
+		if p < 1 {
+			return placementCriterion{}, fmt.Errorf("для критерия %s нужно хотя бы один пункт, задано %d", key, p)
+		}
+		criterion := placementCriterion{key: key, facilities: p}
+		var objective placementObjective
+		if key == objectivePCenter {
+			criterion.title = fmt.Sprintf("взвешенный p-центр, p = %d", p)
+			criterion.valueLabel = "Наибольшее взвешенное время до ближайшего пункта"
+			objective = weightedPCenterObjective(demand)
+		} else {
+			criterion.title = fmt.Sprintf("взвешенная p-медиана, p = %d", p)
+			criterion.valueLabel = "Суммарное взвешенное время до ближайших пунктов"
+			objective = weightedPMedianObjective(demand)
+		}
+		criterion.solve = func(distMatrix DistanceMatrix) placement {
+			return solvePlacement(distMatrix.Dist, p, objective)
+		}
+		return criterion, nil
+	}
+	return placementCriterion{}, fmt.Errorf("неизвестный критерий %q, доступны: %s", key, strings.Join(objectiveNames, ", "))
+}
+
+// Функция для построения таблицы решения задачи размещения на одной сети
+func placementTable(distMatrix DistanceMatrix, criterion placementCriterion, solution placement) [][]string {
+	method := "эвристика (жадный выбор и замены)"
+	if solution.exact {
+		method = "полный перебор"
+	}
+	table := [][]string{
+		{"Название", "Результат"},
+		{"Критерий", criterion.title},
+		{"Пункты обслуживания", formatFacilities(distMatrix.Vertices, solution.facilities)},
+		{criterion.valueLabel, formatDistance(solution.value)},
+		{"Метод", method},
+	}
+	if len(solution.facilities) == 0 {
+		return table
+	}
+	for v, vertex := range distMatrix.Vertices {
+		nearest := solution.facilities[0]
+		for _, f := range solution.facilities {
+			if distMatrix.Dist[f][v] < distMatrix.Dist[nearest][v] {
+				nearest = f
+			}
+		}
+		table = append(table, []string{
+			fmt.Sprintf("Вершина %s", vertex),
+			fmt.Sprintf("пункт %s, %s", distMatrix.Vertices[nearest], formatDistance(distMatrix.Dist[nearest][v])),
+		})
+	}
+	return table
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Функция для генерации небольшой связной сети со случайным спросом
+func generatePlacementInstance(rng *rand.Rand) (DistanceMatrix, []float64) {
+	n := 6 + rng.Intn(8)
+	graph := generateDirectedNetwork(rng, n, 0, 0.35)
+	for i := 1; i < n; i++ {
+		graph.SetUndirectedEdge(i, rng.Intn(i), float64(1+rng.Intn(20)))
+	}
+	demand := make([]float64, n)
+	for i := range demand {
+		demand[i] = float64(1 + rng.Intn(100))
+	}
+	return dijkstraAll(graph), demand
+}
+
+func TestHeuristicPlacementMatchesExact(t *testing.T) {
+	tests := []struct {
+		name      string
+		objective func(demand []float64) placementObjective
+	}{
+		{objectivePCenter, weightedPCenterObjective},
+		{objectivePMedian, weightedPMedianObjective},
+		{objectiveCover, func(demand []float64) placementObjective { return uncoveredDemandObjective(demand, 15) }},
+	}
+	// Жадный выбор с заменами — эвристика и изредка останавливается
+	// в локальном минимуме, поэтому проверяется доля совпадений
+	const minMatchShare = 0.95
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			matches, total := 0, 0
+			for instance := 0; instance < 200; instance++ {
+				distMatrix, demand := generatePlacementInstance(rng)
+				objective := tt.objective(demand)
+				for p := 1; p <= 3; p++ {
+					exact := exactPlacement(distMatrix.Dist, p, objective)
+					heuristic := interchangePlacement(distMatrix.Dist, greedyPlacement(distMatrix.Dist, p, objective), objective)
+
+					if len(heuristic.facilities) != p || len(slices.Compact(slices.Clone(heuristic.facilities))) != p {
+						t.Fatalf("эвристика вернула некорректный набор %v для p = %d", heuristic.facilities, p)
+					}
+					if value := objective(distMatrix.Dist, heuristic.facilities); value != heuristic.value {
+						t.Fatalf("значение набора %v = %v, эвристика сообщила %v", heuristic.facilities, value, heuristic.value)
+					}
+					if heuristic.value < exact.value {
+						t.Fatalf("эвристика %v лучше полного перебора %v", heuristic.value, exact.value)
+					}
+					if p == 1 && heuristic.value != exact.value {
+						t.Fatalf("для одного пункта эвристика %v не совпала с перебором %v", heuristic.value, exact.value)
+					}
+					total++
+					if heuristic.value == exact.value {
+						matches++
+					}
+				}
+			}
+			t.Logf("совпадений с полным перебором: %d из %d", matches, total)
+			if share := float64(matches) / float64(total); share < minMatchShare {
+				t.Errorf("доля совпадений с полным перебором %.3f меньше %.2f", share, minMatchShare)
+			}
+		})
+	}
+}
+
+func TestSolvePlacementUsesExactForSmallInstances(t *testing.T) {
+	distMatrix, demand := generatePlacementInstance(rand.New(rand.NewSource(2)))
+	objective := weightedPCenterObjective(demand)
+	for p := 1; p <= 3; p++ {
+		solution := solvePlacement(distMatrix.Dist, p, objective)
+		want := exactPlacement(distMatrix.Dist, p, objective)
+		if !solution.exact || solution.value != want.value {
+			t.Errorf("p = %d: решение %+v, ожидался полный перебор со значением %v", p, solution, want.value)
+		}
+	}
+}
+
+func TestForEachCombination(t *testing.T) {
+	tests := []struct{ n, p, want int }{
+		{5, 1, 5}, {5, 2, 10}, {6, 3, 20}, {4, 4, 1}, {3, 4, 0}, {3, 0, 0},
+	}
+	for _, tt := range tests {
+		count := 0
+		forEachCombination(tt.n, tt.p, func([]int) { count++ })
+		if count != tt.want || (tt.p >= 1 && tt.p <= tt.n && binomial(tt.n, tt.p) != tt.want) {
+			t.Errorf("сочетаний из %d по %d: %d, ожидалось %d", tt.n, tt.p, count, tt.want)
+		}
+	}
+}
+
+func TestParseDemand(t *testing.T) {
+	vertices := []string{"1", "2", "3"}
+	tests := []struct {
+		spec    string
+		want    []float64
+		wantErr bool
+	}{
+		{"", []float64{1, 1, 1}, false},
+		{"1=3200, 2=1500, 3=0", []float64{3200, 1500, 0}, false},
+		{"1=3200,2=1500", nil, true}, // спрос вершины 3 не задан
+		{"1=3200,2=1500,4=10", nil, true},
+		{"1=3200,2=-1,3=5", nil, true},
+		{"data/missing_demand.csv", nil, true},
+		{"missing/demand", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDemand(tt.spec, vertices)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("parseDemand(%q) = %v, %v; ожидалось %v, ошибка %v", tt.spec, got, err, tt.want, tt.wantErr)
+		}
+		if err != nil && strings.Contains(tt.spec, "/") && !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("parseDemand(%q): ожидалась ошибка открытия файла, получено %v", tt.spec, err)
+		}
+	}
+}
+
+func TestCoverageZeroDemand(t *testing.T) {
+	distMatrix, demand := generatePlacementInstance(rand.New(rand.NewSource(1)))
+	for i := range demand {
+		demand[i] = 0
+	}
+	stats := coverageStats{coverTime: 10, full: map[string]int{}, share: map[string]float64{}}
+	stats.add(distMatrix, demand, 2)
+	tables := [][][]string{
+		coverageTable(distMatrix, demand, 10, 2),
+		coverageProbabilityTable(stats, 1),
+		demandTable(distMatrix.Vertices, demand),
+	}
+	for _, table := range tables {
+		for _, row := range table {
+			for _, cell := range row {
+				if strings.Contains(cell, "NaN") || strings.Contains(cell, "Inf") {
+					t.Errorf("строка %v: неопределённое значение при нулевом спросе", row)
+				}
+			}
+		}
+	}
+}
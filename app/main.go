@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/csv"
+	"flag"
 	"fmt"
 	"log"
 	"math"
@@ -9,8 +10,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,8 +24,69 @@ import (
 
 var peaks []string
 
+// Отчёт с результатами расчёта, записывается в results.html в конце processData
+var resultsReport *report
+
+var (
+	samplerFlag      = flag.String("sampler", "mc", "генератор весов рёбер для моделирования: mc, antithetic, lhs, sobol, halton")
+	iterationsFlag   = flag.Int("iterations", 10000, "число итераций моделирования размещения")
+	compareFlag      = flag.Bool("compare-samplers", false, "сравнить все генераторы по оценкам и их дисперсии; каждый получает бюджет -iterations")
+	replicationsFlag = flag.Int("replications", 10, "число независимых повторов при сравнении генераторов")
+	apspFlag         = flag.String("apsp", backendAuto, "алгоритм кратчайших путей: auto, dense, heap, floyd, johnson")
+	objectivesFlag   = flag.String("objective", "radius,pcenter", "критерии размещения через запятую: radius, pcenter, pmedian, cover (покрытие за -cover-time)")
+	facilitiesFlag   = flag.Int("p", 2, "число пунктов обслуживания для критериев pcenter, pmedian и cover, 0 — не решать их")
+	coverTimeFlag    = flag.Float64("cover-time", 0, "норматив времени доезда в минутах для анализа покрытия, 0 — не анализировать")
+	demandFlag       = flag.String("demand", "", "спрос по вершинам: CSV-файл \"вершина,спрос\" или список вида 1=3200,2=1500")
+	criterionFlag    = flag.String("criterion", eccentricitySum, "критерий оптимальной вершины: ext, int, max, sum, weighted")
+	weightFlag       = flag.Float64("criterion-weight", 0.5, "вес внешнего радиуса для критерия weighted")
+	sensitivityFlag  = flag.Float64("sensitivity", 10, "изменение среднего времени проезда по ребру в процентах для анализа чувствительности, 0 — не анализировать")
+	sensitivityRange = flag.Bool("sensitivity-range", false, "в анализе чувствительности менять время проезда до наблюдавшихся минимума и максимума")
+	closuresFlag     = flag.String("closures", "", "сценарии перекрытий рёбер: рёбра через запятую, сценарии через точку с запятой, например 1:3,5:6;2:4")
+	toleranceFlag    = flag.Float64("closure-tolerance", 10, "допустимое отставание от лучшей вершины в процентах, при котором вершина считается устойчивой к перекрытию")
+	edgeFailureFlag  = flag.Float64("edge-failure", 0, "вероятность перекрытия каждого ребра в моделировании")
+	whatIfFlag       = flag.String("whatif", "", "CSV-файл с гипотетическими рёбрами \"ребро,normal,E,Omega\" или \"ребро,uniform,a,b\" для сравнения с исходной сетью")
+	departureFlag    = flag.String("departure", "", "время отправления вида 18:00 для расчёта радиусов по зависящим от времени кратчайшим путям")
+	embedFlag        = flag.String("embed", embedNone, "встраивание изображений в отчёт: none — ссылки на файлы, png — base64, svg — графики в SVG")
+	interactiveFlag  = flag.Bool("interactive", false, "интерактивный отчёт: сортировка и фильтр таблиц, диаграммы с подсказками, вкладки критериев и времени отправления")
+	convergenceFlag  = flag.Float64("convergence-tolerance", 0.01, "допуск отклонения текущей доли побед от итоговой для таблицы сходимости моделирования")
+	formatsFlag      = flag.String("report-formats", "", "дополнительные форматы отчёта через запятую: md — Markdown, pdf — PDF")
+	mapGeoJSONFlag   = flag.String("map-geojson", "", "GeoJSON-файл с границами районов для подложки карты размещения")
+	mapTilesFlag     = flag.String("map-tiles", "", "каталог тайлов карты вида z/x/y.png для подложки карты размещения")
+	mapZoomFlag      = flag.Int("map-zoom", 15, "масштаб тайлов из каталога -map-tiles")
+)
+
 func main() {
-	createHTMLFile("Результаты")
+	flag.Parse()
+	sampler, err := newSampler(*samplerFlag)
+	if err != nil {
+		log.Fatalf("Некорректный генератор: %v", err)
+	}
+	eccentricity, err = newEccentricityCriterion(*criterionFlag, *weightFlag)
+	if err != nil {
+		log.Fatalf("Некорректный критерий оптимальности: %v", err)
+	}
+	if *edgeFailureFlag < 0 || *edgeFailureFlag >= 1 {
+		log.Fatalf("Вероятность перекрытия ребра должна быть от 0 до 1, задано %g", *edgeFailureFlag)
+	}
+	if *compareFlag && *replicationsFlag < 2 {
+		log.Fatalf("Для сравнения генераторов нужно хотя бы 2 повтора, задано %d", *replicationsFlag)
+	}
+	if *convergenceFlag <= 0 || *convergenceFlag >= 1 {
+		log.Fatalf("Допуск сходимости должен быть от 0 до 1, задано %g", *convergenceFlag)
+	}
+	if !slices.Contains(embedModes, *embedFlag) {
+		log.Fatalf("Некорректный способ встраивания изображений: %s", *embedFlag)
+	}
+	for _, format := range selectedReportFormats() {
+		if !slices.Contains(reportFormats, format) {
+			log.Fatalf("Некорректный формат отчёта: %s", format)
+		}
+	}
+	if !slices.Contains(shortestPathBackends, *apspFlag) {
+		log.Fatalf("Некорректный алгоритм кратчайших путей: %s", *apspFlag)
+	}
+
+	resultsReport = newReport("Результаты")
 
 	// Проверка наличия папки и создание её, если нет
 	path := "./data"
@@ -35,7 +97,7 @@ func main() {
 		}
 		fmt.Println("Директория 'data' создана. Начинаем процесс создания новых данных.")
 		generateNewTable()
-		processData("./data/new_data.csv")
+		processData("./data/new_data.csv", sampler)
 	} else {
 		files, err := listFilesInDirectory(path)
 		if err != nil {
@@ -45,7 +107,7 @@ func main() {
 		if len(files) == 0 {
 			fmt.Println("Директория 'data' пуста. Начинаем процесс создания новых данных.")
 			generateNewTable()
-			processData("./data/new_data.csv")
+			processData("./data/new_data.csv", sampler)
 		} else {
 			var choice string
 			fmt.Println("Директория 'data' не пуста.")
@@ -68,10 +130,10 @@ func main() {
 				if err != nil {
 					log.Fatalf("Ошибка при загрузке данных из файла: %v", err)
 				}
-				processData(selectedFile)
+				processData(selectedFile, sampler)
 			} else if choice == "2" {
 				generateNewTable()
-				processData("./data/new_data.csv")
+				processData("./data/new_data.csv", sampler)
 			} else {
 				log.Fatalf("Некорректный выбор: %s", choice)
 			}
@@ -79,13 +141,13 @@ func main() {
 	}
 }
 
-func processData(filePath string) {
+func processData(filePath string, sampler Sampler) {
 	// Вывод таблицы в браузер
 	data, err := loadDataFromFile(filePath)
 	if err != nil {
 		log.Fatalf("Ошибка при загрузке данных из файла: %v", err)
 	}
-	appendTableToHTML("Исходная таблица данных", data)
+	resultsReport.addRows("Исходная таблица данных", data)
 
 	// Повторное чтение данных из файла
 	dataFile, err := os.Open(filePath)
@@ -95,13 +157,16 @@ func processData(filePath string) {
 	defer dataFile.Close()
 
 	reader := csv.NewReader(dataFile)
-	// Пропустить первые три строки (заголовки)
-	for i := 0; i < 4; i++ {
-		_, err = reader.Read()
+	// Пропустить первые четыре строки (заголовки); во второй из них —
+	// дата сбора замеров, в последней — время среза каждого столбца замеров
+	headers := make([][]string, 4)
+	for i := range headers {
+		headers[i], err = reader.Read()
 		if err != nil {
 			log.Fatalf("Unable to parse file as CSV: %v", err)
 		}
 	}
+	dateHeader, slotHeader := headers[1], headers[3]
 
 	// Чтение оставшихся данных с обработкой неправильного количества полей
 	data = nil
@@ -119,43 +184,316 @@ func processData(filePath string) {
 	edges, peaks = extractEdgesAndPoints(data)
 	log.Printf("Edges: %v, Peaks: %v", edges, peaks)
 
+	// Критерии размещения и спрос по вершинам
+	demand, err := parseDemand(*demandFlag, peaks)
+	if err != nil {
+		log.Fatalf("Некорректный спрос: %v", err)
+	}
+	if *facilitiesFlag < 0 {
+		log.Fatalf("Число пунктов не может быть отрицательным, задано %d", *facilitiesFlag)
+	}
+	if *coverTimeFlag > 0 && *facilitiesFlag < 1 {
+		log.Fatalf("Для анализа покрытия за -cover-time нужно хотя бы один пункт, задано -p %d", *facilitiesFlag)
+	}
+	var criteria []placementCriterion
+	for _, key := range strings.Split(*objectivesFlag, ",") {
+		key = strings.TrimSpace(key)
+		// -p 0 отключает критерии с p пунктами, остаётся только radius
+		if *facilitiesFlag == 0 && slices.Contains(objectiveNames, key) && key != objectiveRadius {
+			log.Printf("Criterion %s skipped: -p 0", key)
+			continue
+		}
+		criterion, err := newPlacementCriterion(key, *facilitiesFlag, demand, *coverTimeFlag)
+		if err != nil {
+			log.Fatalf("Некорректный критерий размещения: %v", err)
+		}
+		criteria = append(criteria, criterion)
+	}
+
+	var overlay []edgeDistribution
+	if *whatIfFlag != "" {
+		overlay, err = loadWhatIf(*whatIfFlag)
+		if err != nil {
+			log.Fatalf("Некорректный файл изменений сети: %v", err)
+		}
+	}
+
 	// Вычисление результатов
 	results1, results2 := calculateResults(data)
 	distribution := calculateDistribution(results1, results2, edges)
-	appendImageToHTML("Граф", "graph.png")
+	createNetworkGraph(peaks, distribution, "Сеть средних времён проезда", "graph.png")
+	resultsReport.addFigure("Граф", "graph.png")
 	// Вывод результатов в браузер
-	appendTableToHTML("Результаты 1", results1)
-	appendTableToHTML("Результаты 2", results2)
-	appendTableToHTML("Распределение", distribution)
+	resultsReport.addRows("Результаты 1", results1)
+	resultsReport.addRows("Результаты 2", results2)
+	resultsReport.addRows("Распределение", distributionTable(distribution))
+	if schedules, err := buildEdgeSchedules(dateHeader, slotHeader, data); err != nil {
+		log.Printf("Unable to read measurements by date and time: %v", err)
+	} else if err := createSchedulePlots(schedules, "heatmaps.png", "trends.png"); err != nil {
+		log.Printf("Unable to create time-of-day plots: %v", err)
+	} else {
+		resultsReport.addFigure("Время проезда по датам и временным срезам", "heatmaps.png")
+		resultsReport.addFigure("Среднее время проезда по часам с 95% доверительным интервалом", "trends.png")
+	}
+	// Рисунки по рёбрам собраны в один раздел, чтобы не загромождать оглавление
+	diagnosticsAdded := false
+	for k, record := range data {
+		filename := fmt.Sprintf("diagnostics_%d.png", k+1)
+		if err := createEdgeDiagnostics(record, filename); err != nil {
+			log.Printf("Unable to create diagnostics for edge %s: %v", record[0], err)
+			continue
+		}
+		if !diagnosticsAdded {
+			resultsReport.addText("Диагностика распределений",
+				"Для каждого ребра: гистограмма замеров с плотностями подобранных нормального и равномерного законов, эмпирическая и подобранные функции распределения, графики Q–Q.")
+			diagnosticsAdded = true
+		}
+		resultsReport.addSubfigure(fmt.Sprintf("Ребро %s", record[0]), filename)
+	}
 
-	// Генерация случайного числа
+	// Генерация случайного числа, общего для всех рёбер
 	rand.Seed(time.Now().UnixNano())
 	randomNumber := rand.Float64()
-	appendRandomNumberToHTML(randomNumber)
+	resultsReport.addText("Случайное число", fmt.Sprintf("%f", randomNumber))
 
-	randomNetwork := generateRandomNetwork(peaks, distribution, randomNumber)
-	appendTableToHTML("Случайная сеть", randomNetwork)
-	distMatrix := dijkstraAll(randomNetwork)
-	appendTableToHTML("Матрица расстояний", distMatrix)
+	u := make([]float64, len(distribution))
+	for i := range u {
+		u[i] = randomNumber
+	}
+	randomNetwork := generateRandomNetwork(peaks, distribution, u)
+	resultsReport.addRows("Случайная сеть", randomNetwork.Table())
+	resultsReport.addRows("Связность сети", connectivityTable(randomNetwork))
+	if warning := connectivityWarning(randomNetwork); warning != "" {
+		resultsReport.addWarning(warning)
+	}
+	distMatrix := shortestDistances(randomNetwork)
+	resultsReport.addRows("Матрица расстояний", distMatrix.Table())
 
 	extRad, intRad := calculateExternalDistances(distMatrix), calculateInternalDistances(distMatrix)
 	extIntTable := calculateAndHighlightModelingResults(intRad, extRad, peaks)
-	appendTableToHTML("Результаты модуляции", extIntTable)
+	resultsReport.addTable("Результаты модуляции", extIntTable)
+
+	// Маршрут, определяющий внешний радиус оптимальной вершины
+	if optimal := optimalVertexIndex(intRad, extRad); optimal >= 0 {
+		farthest := farthestVertex(distMatrix, optimal)
+		routeTitle := fmt.Sprintf("Маршрут в оптимальную вершину %s от самой удалённой вершины %s: %s",
+			peaks[optimal], peaks[farthest], formatPath(peaks, distMatrix.Path(farthest, optimal)))
+		resultsReport.addRows(routeTitle, routeLegsTable(randomNetwork, distMatrix, farthest, optimal))
+	}
+	resultsReport.addRows("Кратчайшие маршруты между всеми парами вершин", allRoutesTable(distMatrix))
+
+	if *demandFlag != "" {
+		resultsReport.addRows("Спрос по вершинам", demandTable(peaks, demand))
+	}
+	for _, criterion := range criteria {
+		if criterion.key == objectiveRadius {
+			continue
+		}
+		resultsReport.addRows(fmt.Sprintf("Размещение пунктов (%s)", criterion.title), placementTable(distMatrix, criterion, criterion.solve(distMatrix)))
+	}
+
+	if *coverTimeFlag > 0 {
+		resultsReport.addRows(fmt.Sprintf("Покрытие за %g мин", *coverTimeFlag), coverageTable(distMatrix, demand, *coverTimeFlag, *facilitiesFlag))
+	}
+
+	// Абсолютный центр: точка размещения может лежать внутри ребра
+	resultsReport.addRows("Абсолютный 1-центр на рёбрах (Хакими)", absoluteCenterTable(peaks, solveAbsoluteCenter(randomNetwork, distMatrix, demand, 1)))
+	if *facilitiesFlag > 1 {
+		resultsReport.addRows(fmt.Sprintf("Абсолютный p-центр на рёбрах, p = %d", *facilitiesFlag),
+			absoluteCenterTable(peaks, solveAbsoluteCenter(randomNetwork, distMatrix, demand, *facilitiesFlag)))
+	}
 
 	histogramFilename := "histogram.png"
-	createHistogram(extIntTable, "Гистограмма суммы радиусов", histogramFilename)
-	appendImageToHTML("Гистограмма суммы радиусов", histogramFilename)
+	histogramTitle := fmt.Sprintf("Гистограмма: %s", strings.ToLower(eccentricity.title()))
+	createHistogram(extIntTable, histogramTitle, histogramFilename)
+	resultsReport.addFigure(histogramTitle, histogramFilename)
+
+	// Чувствительность оптимальной вершины к времени проезда по отдельным рёбрам
+	if *sensitivityFlag > 0 || *sensitivityRange {
+		bounds := percentBounds(distribution, *sensitivityFlag)
+		change := fmt.Sprintf("±%g%%", *sensitivityFlag)
+		if *sensitivityRange {
+			bounds, err = observedBounds(data)
+			if err != nil {
+				log.Fatalf("Некорректные данные для анализа чувствительности: %v", err)
+			}
+			change = "наблюдавшиеся минимум и максимум"
+		}
+		sensitivity := analyzeSensitivity(peaks, distribution, bounds)
+		tornadoTitle := fmt.Sprintf("Чувствительность оптимальной вершины (%s)", change)
+		createTornadoChart(distribution, sensitivity, tornadoTitle, "tornado.png")
+		resultsReport.addFigure(tornadoTitle, "tornado.png")
+		resultsReport.addRows(fmt.Sprintf("Изменения рёбер, меняющие оптимальную вершину (%s)", change),
+			sensitivityFlipTable(peaks, distribution, sensitivity))
+	}
+
+	// Кратчайшие пути с временем проезда, зависящим от времени отправления
+	if functions, err := buildTravelTimeFunctions(slotHeader, data); err != nil {
+		resultsReport.addWarning(fmt.Sprintf("расчёт по времени отправления невозможен: %v", err))
+	} else {
+		timeNetwork := newTimeDependentNetwork(peaks, distribution, functions)
+		if *departureFlag != "" {
+			departure, err := parseSlot(*departureFlag)
+			if err != nil {
+				log.Fatalf("Некорректное время отправления: %v", err)
+			}
+			departureMatrix := timeNetwork.shortestPaths(departure)
+			resultsReport.addRows(fmt.Sprintf("Время в пути при отправлении в %s", formatSlot(departure)), departureMatrix.Table())
+			resultsReport.addTable(fmt.Sprintf("Радиусы при отправлении в %s", formatSlot(departure)),
+				calculateAndHighlightModelingResults(calculateInternalDistances(departureMatrix), calculateExternalDistances(departureMatrix), peaks))
+		}
+		dayTable, daySummary := timeOfDayTables(timeNetwork, functions)
+		resultsReport.addTable(fmt.Sprintf("Критерий удалённости по времени отправления (%s)", strings.ToLower(eccentricity.title())), dayTable)
+		resultsReport.addRows("Размещение в течение дня", daySummary)
+		for _, row := range dayTable.Rows {
+			resultsReport.addChart(fmt.Sprintf("Критерий удалённости при отправлении в %s", row[0].Text), "",
+				timeOfDayChart(timeNetwork.vertices, row), "departure")
+		}
+	}
 
-	log.Printf("Generating full histogram with %d peaks", len(peaks))
-	generateFullHist(results1, results2, "Гистограмма рамещения", "full_histogram.png")
-	appendImageToHTML("Гистограмма рамещения", "full_histogram.png")
+	// Перекрытия рёбер на сети средних времён проезда
+	closures, err := parseClosures(*closuresFlag, distribution)
+	if err != nil {
+		log.Fatalf("Некорректные сценарии перекрытий: %v", err)
+	}
+	baseline := evaluateClosure(peaks, distribution, nil)
+	single := singleClosures(peaks, distribution)
+	if len(closures) > 0 {
+		var scenarios []closureScenario
+		for _, closed := range closures {
+			scenarios = append(scenarios, evaluateClosure(peaks, distribution, closed))
+		}
+		resultsReport.addRows("Сценарии перекрытий (сеть средних времён проезда)", closureTable(peaks, distribution, baseline, scenarios))
+	}
+	resultsReport.addRows("Перекрытие одного ребра (сеть средних времён проезда)", closureTable(peaks, distribution, baseline, single))
+	resultsReport.addTable(fmt.Sprintf("Устойчивость вершин к перекрытию одного ребра (допуск %g%%)", *toleranceFlag),
+		closureRobustnessTable(peaks, single, *toleranceFlag))
+	if warning := closureWarning(distribution, single); warning != "" {
+		resultsReport.addWarning(warning)
+	}
+
+	log.Printf("Simulating placement with %d peaks, sampler %s", len(peaks), sampler.Name())
+	options := simulationOptions{
+		criteria:   criteria,
+		coverTime:  *coverTimeFlag,
+		facilities: *facilitiesFlag,
+		demand:     demand,
+		failure:    *edgeFailureFlag,
+	}
+	simulation := simulateOptimalVertices(peaks, distribution, sampler, *iterationsFlag, options)
+	for _, criterion := range criteria {
+		title := fmt.Sprintf("Гистограмма размещения (%s)", criterion.title)
+		filename := "full_histogram.png"
+		if criterion.key != objectiveRadius {
+			filename = fmt.Sprintf("full_histogram_%s.png", criterion.key)
+		}
+		generateFullHist(simulation, criterion, title, filename)
+		resultsReport.addChart(title, filename, placementChart(simulation, criterion), "objectives")
+	}
+	for _, criterion := range criteria {
+		if criterion.facilities > 1 {
+			resultsReport.addRows(fmt.Sprintf("Частота оптимальных наборов пунктов (%s)", criterion.title),
+				placementFrequencyTable(simulation.placements[criterion.key], simulation.iterations))
+		}
+	}
+	resultsReport.addRows("Итоги моделирования", simulationSummaryTable(simulation))
+
+	// Сходимость долей побед по ходу моделирования
+	shares := runningShares(peaks, simulation)
+	convergenceTitle := "Сходимость долей побед вершин"
+	createConvergenceChart(peaks, shares, *convergenceFlag, convergenceTitle, "convergence.png")
+	resultsReport.addFigure(convergenceTitle, "convergence.png")
+	resultsReport.addTable("Стабилизация долей побед", convergenceTable(peaks, shares, *convergenceFlag))
+	if warning := convergenceWarning(shares, *convergenceFlag); warning != "" {
+		resultsReport.addWarning(warning)
+	}
+
+	// Карта вершин с подложкой из локальных файлов
+	background := mapBackground{tiles: *mapTilesFlag, zoom: *mapZoomFlag}
+	if *mapGeoJSONFlag != "" {
+		background.shapes, err = loadGeoJSON(*mapGeoJSONFlag)
+		if err != nil {
+			log.Fatalf("Некорректная подложка карты: %v", err)
+		}
+	}
+	mapTitle := "Карта вершин: доля итераций, в которых вершина оптимальна"
+	if createPlacementMap(peaks, distribution, simulation, background, mapTitle, "map.png") {
+		resultsReport.addFigure(mapTitle, "map.png")
+	} else {
+		resultsReport.addWarning("карта вершин не построена: координаты известны не для всех вершин.")
+	}
 
+	// Сравнение вершин по ожидаемому радиусу и показателям риска
+	risks := calculateVertexRisks(eccentricityValues(simulation.externalRadii, simulation.internalRadii))
+	riskComparison, riskSummary := riskTables(peaks, risks)
+	resultsReport.addTable(fmt.Sprintf("Сравнение вершин по критериям риска (%s)", strings.ToLower(eccentricity.title())), riskComparison)
+	resultsReport.addRows("Рекомендуемая вершина по критериям риска", riskSummary)
+
+	// Распределения радиусов по всем смоделированным связным сетям
+	if err := createRadiusPlots(peaks, simulation, "radii_violin.png", "radii_cdf.png"); err != nil {
+		log.Printf("Unable to create radius plots: %v", err)
+	} else {
+		resultsReport.addFigure("Распределения радиусов вершин", "radii_violin.png")
+		resultsReport.addFigure("Функции распределения радиусов вершин", "radii_cdf.png")
+	}
+	resultsReport.addRows(fmt.Sprintf("Распределения радиусов и отставание от лучшей вершины (%s)", strings.ToLower(eccentricity.title())),
+		radiusDistributionTable(peaks, simulation))
+
+	if *coverTimeFlag > 0 {
+		resultsReport.addRows(fmt.Sprintf("Вероятность полного покрытия за %g мин, p = %d", *coverTimeFlag, *facilitiesFlag),
+			coverageProbabilityTable(simulation.coverage, simulation.iterations))
+	}
+	if simulation.disconnected > 0 {
+		resultsReport.addWarning(fmt.Sprintf("в %d из %d смоделированных сетей есть недостижимые вершины, такие сети не учтены в гистограммах размещения.",
+			simulation.disconnected, simulation.iterations))
+	}
+
+	// Сравнение с сетью, дополненной гипотетическими рёбрами и вершинами
+	if len(overlay) > 0 {
+		whatIfPoints, whatIfDistribution := applyWhatIf(peaks, distribution, overlay)
+		resultsReport.addRows("Изменения сети", distributionTable(overlay))
+		log.Printf("Simulating what-if network with %d peaks", len(whatIfPoints))
+		variant := simulateOptimalVertices(whatIfPoints, whatIfDistribution, sampler, *iterationsFlag, simulationOptions{failure: *edgeFailureFlag})
+		whatIfTitle := "Доли побед вершин: исходная сеть и сеть с изменениями"
+		createWhatIfChart(peaks, simulation, whatIfPoints, variant, whatIfTitle, "whatif.png")
+		resultsReport.addFigure(whatIfTitle, "whatif.png")
+		resultsReport.addRows("Сравнение исходной сети и сети с изменениями", whatIfTable(peaks, simulation, whatIfPoints, variant))
+	}
+
+	if *compareFlag {
+		log.Printf("Comparing samplers: %d iterations, %d replications", *iterationsFlag, *replicationsFlag)
+		estimates, variances := compareSamplers(distribution, samplerNames, *iterationsFlag, *replicationsFlag)
+		resultsReport.addRows("Оценки вероятности размещения по генераторам", estimates)
+		resultsReport.addRows("Дисперсия оценок по генераторам", variances)
+	}
+
+	if err := resultsReport.writeHTML("results.html", htmlOptions{embed: *embedFlag, interactive: *interactiveFlag}); err != nil {
+		log.Fatalf("Unable to write HTML file: %v", err)
+	}
+	for _, format := range selectedReportFormats() {
+		write := resultsReport.writeMarkdown
+		if format == reportPDF {
+			write = resultsReport.writePDF
+		}
+		if err := write("results." + format); err != nil {
+			log.Fatalf("Unable to write results.%s: %v", format, err)
+		}
+	}
 	err = openBrowser("results.html")
 	if err != nil {
 		log.Fatalf("Unable to open HTML file in browser: %v", err)
 	}
 }
 
+// Функция для расчёта матрицы расстояний алгоритмом, выбранным флагом -apsp
+func shortestDistances(graph *Graph) DistanceMatrix {
+	distMatrix, err := allPairsShortestPaths(graph, *apspFlag)
+	if err != nil {
+		log.Fatalf("Unable to compute shortest paths: %v", err)
+	}
+	return distMatrix
+}
+
 // Функция для загрузки данных из файла
 func loadDataFromFile(filename string) ([][]string, error) {
 	dataFile, err := os.Open(filename)
@@ -193,70 +531,6 @@ func listFilesInDirectory(directory string) ([]string, error) {
 	return files, err
 }
 
-// Функция для создания общего HTML файла
-func createHTMLFile(title string) {
-	htmlFile, err := os.Create("results.html")
-	if err != nil {
-		log.Fatalf("Unable to create HTML file: %v", err)
-	}
-	defer htmlFile.Close()
-
-	htmlContent := fmt.Sprintf(`
-<!DOCTYPE html>
-<html lang="en">
-<head>
-	<meta charset="UTF-8">
-	<meta name="viewport" content="width=device-width, initial-scale=1.0">
-	<title>%s</title>
-</head>
-<body>
-	<h1>%s</h1>
-`, title, title)
-
-	_, err = htmlFile.WriteString(htmlContent)
-	if err != nil {
-		log.Fatalf("Unable to write to HTML file: %v", err)
-	}
-}
-
-// Функция для добавления таблицы в общий HTML файл
-func appendTableToHTML(title string, data [][]string) {
-	htmlFile, err := os.OpenFile("results.html", os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		log.Fatalf("Unable to open HTML file: %v", err)
-	}
-	defer htmlFile.Close()
-
-	htmlContent := fmt.Sprintf(`
-	<h2>%s</h2>
-	<table border="1">
-		<tr>`, title)
-
-	// Добавление заголовков таблицы
-	for _, header := range data[0] {
-		htmlContent += fmt.Sprintf("<th>%s</th>", header)
-	}
-	htmlContent += "</tr>"
-
-	// Добавление данных таблицы
-	for _, row := range data[1:] {
-		htmlContent += "<tr>"
-		for _, cell := range row {
-			htmlContent += fmt.Sprintf("<td>%s</td>", cell)
-		}
-		htmlContent += "</tr>"
-	}
-
-	htmlContent += `
-	</table>
-`
-
-	_, err = htmlFile.WriteString(htmlContent)
-	if err != nil {
-		log.Fatalf("Unable to write to HTML file: %v", err)
-	}
-}
-
 // Функция для открытия файла в браузере
 func openBrowser(url string) error {
 	var cmd string
@@ -293,26 +567,20 @@ func extractEdgesAndPoints(data [][]string) ([]string, []string) {
 	for point := range pointsSet {
 		points = append(points, point)
 	}
-	sort.Strings(points)
+	sortVertexIDs(points)
 	return edges, points
 }
 
-func appendRandomNumberToHTML(randomNumber float64) {
-	htmlFile, err := os.OpenFile("results.html", os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		log.Fatalf("Unable to open HTML file: %v", err)
-	}
-	defer htmlFile.Close()
-
-	htmlContent := fmt.Sprintf(`
-	<h2>Случайное число</h2>
-	<p>%f</p>
-`, randomNumber)
-
-	_, err = htmlFile.WriteString(htmlContent)
-	if err != nil {
-		log.Fatalf("Unable to write to HTML file: %v", err)
-	}
+// Функция для сортировки идентификаторов вершин: числовые — по значению, остальные — как строки
+func sortVertexIDs(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
 }
 
 func radMatrix(ext, iter []float64, points []string) [][]string {
@@ -364,47 +632,60 @@ func calculateModelingResults(internalDistances, externalDistances []float64) []
 	return results
 }
 
-func calculateAndHighlightModelingResults(internalDistances, externalDistances []float64, points []string) [][]string {
+func calculateAndHighlightModelingResults(internalDistances, externalDistances []float64, points []string) reportTable {
 	matrixSize := len(points)
 	results := make([][]string, matrixSize+1)
 
 	// Инициализация первой строки заголовков
-	results[0] = []string{"Вершина", "Внешний радиус", "Внутренний радиус", "Сумма радиусов"}
-
-	// Переменные для нахождения минимальной суммы радиусов
-	minSumRadius := math.MaxFloat64
-	minIndex := -1
+	results[0] = []string{"Вершина", "Внешний радиус", "Внутренний радиус", eccentricity.title()}
 
 	// Заполнение таблицы данными
 	for i := 0; i < matrixSize; i++ {
-		sumRadius := internalDistances[i] + externalDistances[i]
 		results[i+1] = []string{
 			points[i],
-			fmt.Sprintf("%.2f", externalDistances[i]),
-			fmt.Sprintf("%.2f", internalDistances[i]),
-			fmt.Sprintf("%.2f", sumRadius),
-		}
-		if sumRadius < minSumRadius {
-			minSumRadius = sumRadius
-			minIndex = i + 1 // +1 для учета заголовков
+			formatDistance(externalDistances[i]),
+			formatDistance(internalDistances[i]),
+			formatDistance(eccentricity.value(externalDistances[i], internalDistances[i])),
 		}
 	}
-
-	// Подсветка строки с минимальной суммой радиусов
-	for i := range results[minIndex] {
-		results[minIndex][i] = fmt.Sprintf("<b>%s</b>", results[minIndex][i])
+	table := tableFromRows(results)
+	minIndex := optimalVertexIndex(internalDistances, externalDistances)
+	if minIndex < 0 {
+		// Все радиусы бесконечны — сеть несвязна, подсвечивать нечего
+		return table
 	}
 
-	return results
+	// Подсветка строки с минимальным значением критерия
+	table.highlightRow(minIndex)
+
+	return table
+}
+
+// Функция для нахождения вершины с минимальным значением выбранного критерия
+// удалённости. Вершина с бесконечным радиусом оптимальной быть не может,
+// поэтому для несвязной сети, где бесконечны все радиусы, возвращается -1.
+func optimalVertexIndex(internalDistances, externalDistances []float64) int {
+	minValue := math.Inf(1)
+	minIndex := -1
+	for i := range internalDistances {
+		value := eccentricity.value(externalDistances[i], internalDistances[i])
+		if value < minValue {
+			minValue = value
+			minIndex = i
+		}
+	}
+	return minIndex
 }
 
 // Функция для создания гистограммы
-func createHistogram(data [][]string, title string, filename string) {
-	// Пропустить заголовок и первую строку с названиями столбцов
-	values := make([]float64, len(data)-1)
-	for i, row := range data[1:] {
-		// Очистка значения от HTML-тегов перед парсингом
-		cleanValue := stripHTMLTags(row[3]) // Используем столбец "Сумма радиусов"
+func createHistogram(data reportTable, title string, filename string) {
+	values := make([]float64, len(data.Rows))
+	for i, row := range data.Rows {
+		cleanValue := row[3].Text // Используем столбец критерия удалённости
+		if cleanValue == "∞" {
+			values[i] = math.Inf(1)
+			continue
+		}
 		value, err := strconv.ParseFloat(cleanValue, 64)
 		if err != nil {
 			log.Fatalf("Unable to parse value from data: %v", err)
@@ -412,16 +693,19 @@ func createHistogram(data [][]string, title string, filename string) {
 		values[i] = value
 	}
 
-	// Создание данных для гистограммы
+	// Создание данных для гистограммы. Бесконечные суммы не рисуются,
+	// а отмечаются в подписи вершины.
 	barValues := make(plotter.Values, len(values))
 	for i, v := range values {
-		barValues[i] = v
+		if !math.IsInf(v, 1) {
+			barValues[i] = v
+		}
 	}
 
 	p := plot.New()
 	p.Title.Text = title
 	p.X.Label.Text = "Номер вершины"
-	p.Y.Label.Text = "Сумма радиусов"
+	p.Y.Label.Text = eccentricity.title()
 	p.Y.Min = 0 // Установить минимум оси Y на 0
 
 	bars, err := plotter.NewBarChart(barValues, vg.Points(20))
@@ -433,6 +717,9 @@ func createHistogram(data [][]string, title string, filename string) {
 	labels := make([]string, len(values))
 	for i := range labels {
 		labels[i] = strconv.Itoa(i + 1)
+		if math.IsInf(values[i], 1) {
+			labels[i] += " (∞)"
+		}
 	}
 	p.NominalX(labels...)
 
@@ -440,11 +727,17 @@ func createHistogram(data [][]string, title string, filename string) {
 
 	// Найти индекс минимального значения
 	minIndex := 0
-	for i, v := range barValues {
-		if v < barValues[minIndex] {
+	for i, v := range values {
+		if v < values[minIndex] {
 			minIndex = i
 		}
 	}
+	if math.IsInf(values[minIndex], 1) {
+		if err := savePlot(p, 8*vg.Inch, 4*vg.Inch, filename); err != nil {
+			log.Fatalf("Unable to save bar chart: %v", err)
+		}
+		return
+	}
 
 	highlight, err := plotter.NewBarChart(plotter.Values{barValues[minIndex]}, vg.Points(20))
 	if err != nil {
@@ -453,130 +746,103 @@ func createHistogram(data [][]string, title string, filename string) {
 
 	p.Add(highlight)
 
-	if err := p.Save(8*vg.Inch, 4*vg.Inch, filename); err != nil {
+	if err := savePlot(p, 8*vg.Inch, 4*vg.Inch, filename); err != nil {
 		log.Fatalf("Unable to save bar chart: %v", err)
 	}
 }
 
-// Функция для добавления изображения в HTML
-func appendImageToHTML(title, filename string) {
-	htmlFile, err := os.OpenFile("results.html", os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		log.Fatalf("Unable to open HTML file: %v", err)
-	}
-	defer htmlFile.Close()
-
-	htmlContent := fmt.Sprintf(`
-	<h2>%s</h2>
-	<img src="%s" alt="%s">
-`, title, filename, title)
-
-	_, err = htmlFile.WriteString(htmlContent)
-	if err != nil {
-		log.Fatalf("Unable to write to HTML file: %v", err)
+// Функция для получения столбцов гистограммы размещения по критерию: для
+// одной точки — число побед каждой вершины, для нескольких — самые частые наборы
+func placementBars(simulation placementSimulation, criterion placementCriterion) ([]string, plotter.Values) {
+	counts := simulation.placements[criterion.key]
+
+	var labels []string
+	var barValues plotter.Values
+	if criterion.facilities == 1 {
+		for i, peak := range peaks {
+			labels = append(labels, peak)
+			barValues = append(barValues, float64(counts[formatFacilities(peaks, []int{i})]))
+		}
+	} else {
+		labels = rankedPlacements(counts)
+		if len(labels) > maxHistogramSets {
+			labels = labels[:maxHistogramSets]
+		}
+		for _, set := range labels {
+			barValues = append(barValues, float64(counts[set]))
+		}
 	}
-
-	log.Printf("Added image %s to HTML file with title %s", filename, title)
+	return labels, barValues
 }
 
-// Функция для удаления HTML-тегов
-func stripHTMLTags(input string) string {
-	re := regexp.MustCompile(`<.*?>`)
-	return re.ReplaceAllString(input, "")
+// Функция для получения подписи оси X гистограммы размещения
+func placementAxisLabel(criterion placementCriterion) string {
+	if criterion.facilities > 1 {
+		return "Набор пунктов"
+	}
+	return "Номер вершины"
 }
 
-func generateFullHist(results1, results2 [][]string, title, filename string) {
-	pointSet := make(map[string]int, len(points))
-	for _, val := range points {
-		pointSet[val] = 0
-	}
-
-	// Simulation to populate pointSet
-	for i := 0; i < 10001; i++ {
-		distribution := calculateDistribution(results1, results2, edges)
-		randomNumber := rand.Float64()
-		randomNetwork := generateRandomNetwork(peaks, distribution, randomNumber)
-		distMatrix := dijkstraAll(randomNetwork)
-		extRad, intRad := calculateExternalDistances(distMatrix), calculateInternalDistances(distMatrix)
-		extIntTable := calculateAndHighlightModelingResults(intRad, extRad, peaks)
-		ind, err := extractValueFromFirstColumn(extIntTable)
-		if err != nil {
-			log.Fatalf("Unable to extract value from table: %v", err)
-		}
-		pointSet[ind]++
+// Функция для построения данных интерактивной гистограммы размещения:
+// в подсказке к столбцу — число итераций, доля и её доверительный интервал
+func placementChart(simulation placementSimulation, criterion placementCriterion) reportChart {
+	labels, barValues := placementBars(simulation, criterion)
+	chart := reportChart{Labels: labels, Values: barValues, XLabel: placementAxisLabel(criterion), YLabel: "Число итераций"}
+	for i, label := range labels {
+		chart.Tooltips = append(chart.Tooltips, frequencyTooltip(label, int(barValues[i]), simulation.iterations))
 	}
+	return chart
+}
 
-	// Find the maximum index
-	maxIndex := 0
-	for k := range pointSet {
-		i, err := strconv.Atoi(k)
-		if err != nil {
-			log.Printf("Invalid index %s: %v", k, err)
-			continue
-		}
-		if i > maxIndex {
-			maxIndex = i
-		}
-	}
-
-	// Create data for the histogram
-	barValues := make(plotter.Values, maxIndex)
-	for k, v := range pointSet {
-		i, err := strconv.Atoi(k)
-		if err != nil {
-			log.Printf("Invalid index %s: %v", k, err)
-			continue
-		}
-		if i-1 >= len(barValues) {
-			log.Printf("Index %d is out of bounds for barValues of length %d", i, len(barValues))
-			continue
-		}
-		barValues[i-1] = float64(v)
-	}
+// Функция для построения гистограммы размещения по критерию
+func generateFullHist(simulation placementSimulation, criterion placementCriterion, title, filename string) {
+	labels, barValues := placementBars(simulation, criterion)
 
 	p := plot.New()
 	p.Title.Text = title
-	p.X.Label.Text = "Номер вершины"
+	p.X.Label.Text = placementAxisLabel(criterion)
 	p.Y.Label.Text = "Эффективность расположения"
 	p.Y.Min = 0 // Set the minimum Y axis value to 0
 
-	bars, err := plotter.NewBarChart(barValues, vg.Points(20))
-	if err != nil {
-		log.Fatalf("Unable to create bar chart: %v", err)
-	}
-
-	// Set X-axis labels
-	labels := make([]string, len(barValues))
-	for i := range labels {
-		labels[i] = strconv.Itoa(i + 1) // Correctly match labels with indices
+	if len(barValues) > 0 {
+		bars, err := plotter.NewBarChart(barValues, vg.Points(20))
+		if err != nil {
+			log.Fatalf("Unable to create bar chart: %v", err)
+		}
+		p.NominalX(labels...)
+		p.Add(bars)
 	}
-	p.NominalX(labels...)
-
-	p.Add(bars)
 
-	if err := p.Save(8*vg.Inch, 4*vg.Inch, filename); err != nil {
+	if err := savePlot(p, 8*vg.Inch, 4*vg.Inch, filename); err != nil {
 		log.Fatalf("Unable to save bar chart: %v", err)
 	}
 }
 
-func extractValueFromFirstColumn(matrix [][]string) (string, error) {
-	var value string
+// Наибольшее число наборов пунктов на гистограмме
+const maxHistogramSets = 15
 
-	// Регулярное выражение для поиска конструкции <b>...</b>
-	re := regexp.MustCompile(`<b>([^<]+)</b>`)
-
-	for _, row := range matrix {
-		if len(row) == 0 || len(row) == 8 {
-			continue
+// Функция для получения наборов пунктов по убыванию частоты
+func rankedPlacements(counts map[string]int) []string {
+	sets := make([]string, 0, len(counts))
+	for set := range counts {
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		a, b := counts[sets[i]], counts[sets[j]]
+		if a != b {
+			return a > b
 		}
+		return sets[i] < sets[j]
+	})
+	return sets
+}
 
-		// Проверяем первый столбец на наличие конструкции <b>...</b>
-		match := re.FindStringSubmatch(row[0])
-		if match != nil {
-			value = match[1]
-			break
-		}
+// Функция для построения таблицы частот оптимальных наборов пунктов
+func placementFrequencyTable(counts map[string]int, iterations int) [][]string {
+	table := [][]string{{"Набор пунктов", "Число итераций", "Доля"}}
+	for _, set := range rankedPlacements(counts) {
+		count := counts[set]
+		table = append(table, []string{set, strconv.Itoa(count), fmt.Sprintf("%.4f", float64(count)/float64(iterations))})
 	}
-
-	return value, nil
+	return table
 }
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	imagedraw "image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Радиус Земли в проекции Web Mercator (EPSG:3857), метры
+const mercatorRadius = 6378137
+
+// Функция для перевода широты и долготы в координаты Web Mercator, метры
+func mercator(lat, lon float64) plotter.XY {
+	return plotter.XY{
+		X: mercatorRadius * lon * math.Pi / 180,
+		Y: mercatorRadius * math.Log(math.Tan(math.Pi/4+lat*math.Pi/360)),
+	}
+}
+
+// geoJSONGeometry — геометрия GeoJSON; координаты разбираются по типу
+type geoJSONGeometry struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates"`
+	Geometries  []geoJSONGeometry `json:"geometries"`
+}
+
+// geoJSONObject — объект GeoJSON верхнего уровня: коллекция, объект или геометрия
+type geoJSONObject struct {
+	geoJSONGeometry
+	Features []struct {
+		Geometry *geoJSONGeometry `json:"geometry"`
+	} `json:"features"`
+	Geometry *geoJSONGeometry `json:"geometry"`
+}
+
+// mapShape — контур подложки в координатах Web Mercator
+type mapShape struct {
+	ring   plotter.XYs
+	closed bool // многоугольник (граница района), а не линия
+}
+
+// Функция для загрузки подложки карты из GeoJSON: многоугольники
+// (границы районов) и линии. Точки не рисуются.
+func loadGeoJSON(filename string) ([]mapShape, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var object geoJSONObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("файл %s: %v", filename, err)
+	}
+
+	var geometries []geoJSONGeometry
+	switch object.Type {
+	case "FeatureCollection":
+		for _, feature := range object.Features {
+			if feature.Geometry != nil {
+				geometries = append(geometries, *feature.Geometry)
+			}
+		}
+	case "Feature":
+		if object.Geometry != nil {
+			geometries = append(geometries, *object.Geometry)
+		}
+	default:
+		geometries = append(geometries, object.geoJSONGeometry)
+	}
+
+	var shapes []mapShape
+	for _, geometry := range geometries {
+		parsed, err := geometryShapes(geometry)
+		if err != nil {
+			return nil, fmt.Errorf("файл %s: %v", filename, err)
+		}
+		shapes = append(shapes, parsed...)
+	}
+	return shapes, nil
+}
+
+// Функция для перевода геометрии GeoJSON в контуры
+func geometryShapes(geometry geoJSONGeometry) ([]mapShape, error) {
+	project := func(coordinates [][]float64, closed bool) mapShape {
+		shape := mapShape{closed: closed}
+		for _, c := range coordinates {
+			if len(c) >= 2 {
+				shape.ring = append(shape.ring, mercator(c[1], c[0]))
+			}
+		}
+		return shape
+	}
+
+	var shapes []mapShape
+	var err error
+	switch geometry.Type {
+	case "LineString":
+		var line [][]float64
+		err = json.Unmarshal(geometry.Coordinates, &line)
+		shapes = append(shapes, project(line, false))
+	case "MultiLineString", "Polygon":
+		var lines [][][]float64
+		err = json.Unmarshal(geometry.Coordinates, &lines)
+		for _, line := range lines {
+			shapes = append(shapes, project(line, geometry.Type == "Polygon"))
+		}
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		err = json.Unmarshal(geometry.Coordinates, &polygons)
+		for _, polygon := range polygons {
+			for _, ring := range polygon {
+				shapes = append(shapes, project(ring, true))
+			}
+		}
+	case "GeometryCollection":
+		for _, part := range geometry.Geometries {
+			parsed, err := geometryShapes(part)
+			if err != nil {
+				return nil, err
+			}
+			shapes = append(shapes, parsed...)
+		}
+	case "Point", "MultiPoint":
+	default:
+		return nil, fmt.Errorf("неизвестный тип геометрии %q", geometry.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("геометрия %s: %v", geometry.Type, err)
+	}
+	return shapes, nil
+}
+
+// Функция для границ тайла x, y масштаба zoom в координатах Web Mercator
+func tileBounds(zoom, x, y int) (min, max plotter.XY) {
+	size := 2 * math.Pi * mercatorRadius / math.Exp2(float64(zoom))
+	half := math.Pi * mercatorRadius
+	return plotter.XY{X: -half + float64(x)*size, Y: half - float64(y+1)*size},
+		plotter.XY{X: -half + float64(x+1)*size, Y: half - float64(y)*size}
+}
+
+// Функция для номера тайла, содержащего точку Web Mercator
+func tileIndex(zoom int, point plotter.XY) (x, y int) {
+	size := 2 * math.Pi * mercatorRadius / math.Exp2(float64(zoom))
+	half := math.Pi * mercatorRadius
+	return int(math.Floor((point.X + half) / size)), int(math.Floor((half - point.Y) / size))
+}
+
+// Функция для загрузки тайлов из каталога вида каталог/z/x/y.png (или .jpg),
+// покрывающих прямоугольник view. Тайлы обрезаются по view, потому что
+// изображения не отсекаются по границам графика. Отсутствующие тайлы пропускаются.
+func loadTiles(directory string, zoom int, viewMin, viewMax plotter.XY) []*plotter.Image {
+	var images []*plotter.Image
+	xFirst, yFirst := tileIndex(zoom, plotter.XY{X: viewMin.X, Y: viewMax.Y})
+	xLast, yLast := tileIndex(zoom, plotter.XY{X: viewMax.X, Y: viewMin.Y})
+	missing := 0
+	for x := xFirst; x <= xLast; x++ {
+		for y := yFirst; y <= yLast; y++ {
+			tile, err := loadTile(directory, zoom, x, y)
+			if err != nil {
+				missing++
+				continue
+			}
+			tileMin, tileMax := tileBounds(zoom, x, y)
+			cropMin := plotter.XY{X: math.Max(tileMin.X, viewMin.X), Y: math.Max(tileMin.Y, viewMin.Y)}
+			cropMax := plotter.XY{X: math.Min(tileMax.X, viewMax.X), Y: math.Min(tileMax.Y, viewMax.Y)}
+
+			bounds := tile.Bounds()
+			scaleX := float64(bounds.Dx()) / (tileMax.X - tileMin.X)
+			scaleY := float64(bounds.Dy()) / (tileMax.Y - tileMin.Y)
+			crop := image.Rect(
+				bounds.Min.X+int((cropMin.X-tileMin.X)*scaleX), bounds.Min.Y+int((tileMax.Y-cropMax.Y)*scaleY),
+				bounds.Min.X+int(math.Ceil((cropMax.X-tileMin.X)*scaleX)), bounds.Min.Y+int(math.Ceil((tileMax.Y-cropMin.Y)*scaleY)),
+			)
+			if crop.Empty() {
+				continue
+			}
+			part := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+			imagedraw.Draw(part, part.Bounds(), tile, crop.Min, imagedraw.Src)
+			images = append(images, plotter.NewImage(part, cropMin.X, cropMin.Y, cropMax.X, cropMax.Y))
+		}
+	}
+	if missing > 0 {
+		log.Printf("Map tiles: %d of %d tiles not found in %s for zoom %d", missing, (xLast-xFirst+1)*(yLast-yFirst+1), directory, zoom)
+	}
+	return images
+}
+
+func loadTile(directory string, zoom, x, y int) (image.Image, error) {
+	var lastErr error
+	for _, extension := range []string{".png", ".jpg", ".jpeg"} {
+		file, err := os.Open(filepath.Join(directory, strconv.Itoa(zoom), strconv.Itoa(x), strconv.Itoa(y)+extension))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		tile, _, err := image.Decode(file)
+		file.Close()
+		return tile, err
+	}
+	return nil, lastErr
+}
+
+// Функция для выбора цвета вершины по доле побед: от светло-жёлтого
+// (ни одной победы) до тёмно-красного (наибольшая доля среди вершин)
+func probabilityColor(share, maximum float64) color.Color {
+	t := 0.0
+	if maximum > 0 {
+		t = math.Min(share/maximum, 1)
+	}
+	low, high := [3]float64{255, 237, 160}, [3]float64{189, 0, 38}
+	mix := func(k int) uint8 { return uint8(math.Round(low[k] + t*(high[k]-low[k]))) }
+	return color.RGBA{R: mix(0), G: mix(1), B: mix(2), A: 255}
+}
+
+// mapBackground — подложка карты: GeoJSON и (или) каталог тайлов
+type mapBackground struct {
+	shapes []mapShape
+	tiles  string
+	zoom   int
+}
+
+// Функция для рисования вершин и рёбер сети на карте в проекции Web Mercator.
+// Цвет вершины — доля итераций моделирования, в которых она оптимальна.
+// Возвращает false, если координаты известны не для всех вершин.
+func createPlacementMap(points []string, distribution []edgeDistribution, simulation placementSimulation, background mapBackground, title, filename string) bool {
+	positions := make(plotter.XYs, len(points))
+	index := make(map[string]int, len(points))
+	for i, vertex := range points {
+		lat, lon, ok := vertexLocation(vertex)
+		if !ok {
+			return false
+		}
+		positions[i] = mercator(lat, lon)
+		index[vertex] = i
+	}
+
+	// Область карты — вершины с полями
+	xMin, xMax, yMin, yMax := plotter.XYRange(positions)
+	padX, padY := math.Max((xMax-xMin)*0.15, 100), math.Max((yMax-yMin)*0.15, 100)
+	// Пропорции области выравниваются до загрузки тайлов, чтобы подложка
+	// покрывала всю область рисунка
+	viewMin, viewMax := padToAspect(plotter.XY{X: xMin - padX, Y: yMin - padY}, plotter.XY{X: xMax + padX, Y: yMax + padY})
+
+	p := plot.New()
+	p.Title.Text = title
+	p.HideAxes()
+
+	if background.tiles != "" {
+		for _, tile := range loadTiles(background.tiles, background.zoom, viewMin, viewMax) {
+			p.Add(tile)
+		}
+	}
+	for _, shape := range background.shapes {
+		if len(shape.ring) < 2 {
+			continue
+		}
+		if shape.closed {
+			polygon, err := plotter.NewPolygon(shape.ring)
+			if err != nil {
+				log.Fatalf("Unable to create map polygon: %v", err)
+			}
+			polygon.Color = color.NRGBA{R: 70, G: 130, B: 180, A: 40}
+			polygon.LineStyle.Color = color.RGBA{R: 120, G: 120, B: 120, A: 255}
+			polygon.LineStyle.Width = vg.Points(1)
+			p.Add(polygon)
+			continue
+		}
+		line, err := plotter.NewLine(shape.ring)
+		if err != nil {
+			log.Fatalf("Unable to create map line: %v", err)
+		}
+		line.Color = color.RGBA{R: 170, G: 170, B: 170, A: 255}
+		p.Add(line)
+	}
+
+	for _, d := range distribution {
+		i, okOrigin := index[d.origin]
+		j, okDestination := index[d.destination]
+		if !okOrigin || !okDestination || i == j {
+			continue
+		}
+		line, err := plotter.NewLine(plotter.XYs{positions[i], positions[j]})
+		if err != nil {
+			log.Fatalf("Unable to create edge line: %v", err)
+		}
+		line.Color = color.RGBA{R: 60, G: 60, B: 60, A: 255}
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+	}
+
+	shares := make([]float64, len(points))
+	maximum := 0.0
+	for i, vertex := range points {
+		if simulation.iterations > 0 {
+			shares[i] = float64(simulation.wins[vertex]) / float64(simulation.iterations)
+		}
+		maximum = math.Max(maximum, shares[i])
+	}
+	labels := make([]string, len(points))
+	styles := make([]text.Style, len(points))
+	for i, vertex := range points {
+		marker, err := plotter.NewScatter(plotter.XYs{positions[i]})
+		if err != nil {
+			log.Fatalf("Unable to create vertex marker: %v", err)
+		}
+		marker.GlyphStyle = draw.GlyphStyle{Color: probabilityColor(shares[i], maximum), Radius: vg.Points(8), Shape: draw.CircleGlyph{}}
+		outline, err := plotter.NewScatter(plotter.XYs{positions[i]})
+		if err != nil {
+			log.Fatalf("Unable to create vertex marker: %v", err)
+		}
+		outline.GlyphStyle = draw.GlyphStyle{Color: color.Black, Radius: vg.Points(8), Shape: draw.RingGlyph{}}
+		p.Add(marker, outline)
+
+		labels[i] = fmt.Sprintf("%s (%.2f)", vertex, shares[i])
+		styles[i] = text.Style{Color: color.Black, Font: plot.DefaultFont, Handler: plot.DefaultTextHandler}
+		styles[i].Font.Size = vg.Points(11)
+	}
+	names, err := plotter.NewLabels(plotter.XYLabels{XYs: positions, Labels: labels})
+	if err != nil {
+		log.Fatalf("Unable to create vertex labels: %v", err)
+	}
+	names.TextStyle = styles
+	names.Offset = vg.Point{X: vg.Points(10), Y: vg.Points(6)}
+	p.Add(names)
+
+	// Легенда шкалы цвета
+	for _, share := range []float64{0, maximum / 2, maximum} {
+		sample, err := plotter.NewScatter(plotter.XYs{{}})
+		if err != nil {
+			log.Fatalf("Unable to create legend marker: %v", err)
+		}
+		sample.GlyphStyle = draw.GlyphStyle{Color: probabilityColor(share, maximum), Radius: vg.Points(6), Shape: draw.CircleGlyph{}}
+		p.Legend.Add(fmt.Sprintf("доля побед %.2f", share), sample)
+	}
+	p.Legend.Top = true
+
+	p.X.Min, p.X.Max = viewMin.X, viewMax.X
+	p.Y.Min, p.Y.Max = viewMin.Y, viewMax.Y
+	width := 8 * vg.Inch
+	if err := savePlot(p, width, proportionalHeight(p, width), filename); err != nil {
+		log.Fatalf("Unable to save map: %v", err)
+	}
+	return true
+}
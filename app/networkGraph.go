@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Функция для получения координат вершины из таблицы points (широта, долгота)
+func vertexLocation(vertex string) (lat, lon float64, ok bool) {
+	id, err := strconv.Atoi(vertex)
+	if err != nil {
+		return 0, 0, false
+	}
+	location, found := points[id]
+	if !found {
+		return 0, 0, false
+	}
+	parts := strings.Split(location, ",")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lon, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	return lat, lon, err1 == nil && err2 == nil
+}
+
+// Функция для расположения вершин на рисунке сети. Если координаты известны
+// для всех вершин, используются они (долгота сжата на косинус широты, чтобы
+// сохранить пропорции), иначе — силовая укладка графа.
+func layoutVertices(vertices []string, distribution []edgeDistribution) plotter.XYs {
+	positions := make(plotter.XYs, len(vertices))
+	for i, vertex := range vertices {
+		lat, lon, ok := vertexLocation(vertex)
+		if !ok {
+			return forceLayout(vertices, distribution)
+		}
+		positions[i].X, positions[i].Y = lon, lat
+	}
+	scale := math.Cos(meanOf(latitudes(positions)) * math.Pi / 180)
+	for i := range positions {
+		positions[i].X *= scale
+	}
+	return positions
+}
+
+func latitudes(positions plotter.XYs) []float64 {
+	values := make([]float64, len(positions))
+	for i, position := range positions {
+		values[i] = position.Y
+	}
+	return values
+}
+
+// Функция для силовой укладки графа (Фрюхтерман — Рейнгольд): рёбра
+// притягивают вершины, все вершины отталкиваются друг от друга. Начальное
+// положение — окружность, поэтому рисунок одинаков при каждом запуске.
+func forceLayout(vertices []string, distribution []edgeDistribution) plotter.XYs {
+	n := len(vertices)
+	positions := make(plotter.XYs, n)
+	for i := range positions {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		positions[i].X, positions[i].Y = math.Cos(angle), math.Sin(angle)
+	}
+	index := make(map[string]int, n)
+	for i, vertex := range vertices {
+		index[vertex] = i
+	}
+
+	const iterations = 300
+	k := math.Sqrt(4 / float64(n)) // желаемое расстояние между вершинами
+	temperature := 0.2
+	for step := 0; step < iterations; step++ {
+		shift := make(plotter.XYs, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if i == j {
+					continue
+				}
+				dx, dy := positions[i].X-positions[j].X, positions[i].Y-positions[j].Y
+				distance := math.Max(math.Hypot(dx, dy), 1e-6)
+				force := k * k / distance
+				shift[i].X += dx / distance * force
+				shift[i].Y += dy / distance * force
+			}
+		}
+		for _, d := range distribution {
+			i, okOrigin := index[d.origin]
+			j, okDestination := index[d.destination]
+			if !okOrigin || !okDestination || i == j {
+				continue
+			}
+			dx, dy := positions[i].X-positions[j].X, positions[i].Y-positions[j].Y
+			distance := math.Max(math.Hypot(dx, dy), 1e-6)
+			force := distance * distance / k
+			shift[i].X -= dx / distance * force
+			shift[i].Y -= dy / distance * force
+			shift[j].X += dx / distance * force
+			shift[j].Y += dy / distance * force
+		}
+		for i := range positions {
+			length := math.Max(math.Hypot(shift[i].X, shift[i].Y), 1e-6)
+			move := math.Min(length, temperature)
+			positions[i].X += shift[i].X / length * move
+			positions[i].Y += shift[i].Y / length * move
+		}
+		temperature *= 0.98
+	}
+	return positions
+}
+
+// Функция для рисования сети средних времён проезда: вершины расположены
+// по координатам или силовой укладкой, рёбра подписаны средним временем
+// проезда, оптимальная по средним временам вершина и путь до неё
+// от самой удалённой вершины выделены.
+// Рисунок сохраняется в PNG и рядом в SVG.
+func createNetworkGraph(points []string, distribution []edgeDistribution, title, filename string) {
+	weights := make([]float64, len(distribution))
+	for k, d := range distribution {
+		weights[k] = d.mean
+	}
+	distMatrix := shortestDistances(networkFromWeights(points, distribution, weights))
+	optimal := optimalVertexIndex(calculateInternalDistances(distMatrix), calculateExternalDistances(distMatrix))
+	onRoute := make(map[[2]int]bool)
+	if optimal >= 0 {
+		route := distMatrix.Path(farthestVertex(distMatrix, optimal), optimal)
+		for i := 1; i < len(route); i++ {
+			onRoute[[2]int{route[i-1], route[i]}] = true
+			onRoute[[2]int{route[i], route[i-1]}] = true
+		}
+	}
+
+	positions := layoutVertices(points, distribution)
+	index := make(map[string]int, len(points))
+	for i, vertex := range points {
+		index[vertex] = i
+	}
+
+	p := plot.New()
+	p.Title.Text = title
+	p.HideAxes()
+
+	routeColor := color.RGBA{R: 205, G: 92, B: 92, A: 255}
+	var middles plotter.XYs
+	var edgeLabels []string
+	var edgeStyles []text.Style
+	for _, d := range distribution {
+		i, okOrigin := index[d.origin]
+		j, okDestination := index[d.destination]
+		if !okOrigin || !okDestination || i == j {
+			continue
+		}
+		line, err := plotter.NewLine(plotter.XYs{positions[i], positions[j]})
+		if err != nil {
+			log.Fatalf("Unable to create edge line: %v", err)
+		}
+		line.Color = color.RGBA{R: 150, G: 150, B: 150, A: 255}
+		line.Width = vg.Points(1.5)
+		style := text.Style{Color: color.Black, Font: plot.DefaultFont, Handler: plot.DefaultTextHandler, XAlign: draw.XCenter}
+		style.Font.Size = vg.Points(9)
+		if onRoute[[2]int{i, j}] {
+			line.Color, line.Width = routeColor, vg.Points(3)
+			style.Color = routeColor
+		}
+		p.Add(line)
+		middles = append(middles, plotter.XY{X: (positions[i].X + positions[j].X) / 2, Y: (positions[i].Y + positions[j].Y) / 2})
+		edgeLabels = append(edgeLabels, fmt.Sprintf("%.2f", d.mean))
+		edgeStyles = append(edgeStyles, style)
+	}
+
+	vertexStyles := make([]text.Style, len(points))
+	for i := range points {
+		vertexStyles[i] = text.Style{Color: color.Black, Font: plot.DefaultFont, Handler: plot.DefaultTextHandler}
+		vertexStyles[i].Font.Size = vg.Points(12)
+		if i == optimal {
+			vertexStyles[i].Color = routeColor
+		}
+	}
+	vertices, err := plotter.NewScatter(positions)
+	if err != nil {
+		log.Fatalf("Unable to create vertices: %v", err)
+	}
+	vertices.GlyphStyle = draw.GlyphStyle{Color: color.RGBA{R: 70, G: 130, B: 180, A: 255}, Radius: vg.Points(6), Shape: draw.CircleGlyph{}}
+	p.Add(vertices)
+	if optimal >= 0 {
+		highlight, err := plotter.NewScatter(plotter.XYs{positions[optimal]})
+		if err != nil {
+			log.Fatalf("Unable to create optimal vertex: %v", err)
+		}
+		highlight.GlyphStyle = draw.GlyphStyle{Color: routeColor, Radius: vg.Points(9), Shape: draw.CircleGlyph{}}
+		p.Add(highlight)
+		p.Legend.Add(fmt.Sprintf("оптимальная вершина %s по средним временам и путь до неё от самой удалённой", points[optimal]), highlight)
+		p.Legend.Top = true
+	}
+
+	if len(middles) > 0 {
+		labels, err := plotter.NewLabels(plotter.XYLabels{XYs: middles, Labels: edgeLabels})
+		if err != nil {
+			log.Fatalf("Unable to create edge labels: %v", err)
+		}
+		labels.TextStyle = edgeStyles
+		p.Add(labels)
+	}
+	names, err := plotter.NewLabels(plotter.XYLabels{XYs: positions, Labels: points})
+	if err != nil {
+		log.Fatalf("Unable to create vertex labels: %v", err)
+	}
+	names.TextStyle = vertexStyles
+	names.Offset = vg.Point{X: vg.Points(8), Y: vg.Points(6)}
+	p.Add(names)
+
+	// Поля вокруг вершин, чтобы подписи не выходили за рисунок
+	xMin, xMax, yMin, yMax := plotter.XYRange(positions)
+	padX, padY := math.Max((xMax-xMin)*0.1, 1e-4), math.Max((yMax-yMin)*0.15, 1e-4)
+	p.X.Min, p.X.Max = xMin-padX, xMax+padX
+	p.Y.Min, p.Y.Max = yMin-padY, yMax+padY
+
+	width := 8 * vg.Inch
+	height := proportionalHeight(p, width)
+	if err := savePlot(p, width, height, filename); err != nil {
+		log.Fatalf("Unable to save network graph: %v", err)
+	}
+	if *embedFlag != embedSVG {
+		if err := p.Save(width, height, svgFilename(filename)); err != nil {
+			log.Fatalf("Unable to save network graph: %v", err)
+		}
+	}
+}
+
+// Допустимые пропорции области рисунка: отношение высоты к ширине
+const (
+	minAspect = 0.5
+	maxAspect = 1.0
+)
+
+// Функция для расширения области [low; high] до пропорций от minAspect
+// до maxAspect: слишком вытянутая область дополняется полями по короткой стороне
+func padToAspect(low, high plotter.XY) (plotter.XY, plotter.XY) {
+	width, height := high.X-low.X, high.Y-low.Y
+	if height < width*minAspect {
+		pad := (width*minAspect - height) / 2
+		low.Y, high.Y = low.Y-pad, high.Y+pad
+	} else if height > width*maxAspect {
+		pad := (height/maxAspect - width) / 2
+		low.X, high.X = low.X-pad, high.X+pad
+	}
+	return low, high
+}
+
+// Функция для выбора высоты рисунка, при которой расстояния по горизонтали
+// и вертикали имеют одинаковый масштаб. Диапазоны осей предварительно
+// дополняются до допустимых пропорций, к высоте добавляется заголовок.
+func proportionalHeight(p *plot.Plot, width vg.Length) vg.Length {
+	low, high := padToAspect(plotter.XY{X: p.X.Min, Y: p.Y.Min}, plotter.XY{X: p.X.Max, Y: p.Y.Max})
+	p.X.Min, p.X.Max, p.Y.Min, p.Y.Max = low.X, high.X, low.Y, high.Y
+	height := width * vg.Length((high.Y-low.Y)/(high.X-low.X))
+	if p.Title.Text != "" {
+		height += p.Title.TextStyle.Rectangle(p.Title.Text).Size().Y + p.Title.Padding
+	}
+	return height
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestPadToAspect(t *testing.T) {
+	tests := []struct {
+		name      string
+		low, high plotter.XY
+		wantLow   plotter.XY
+		wantHigh  plotter.XY
+	}{
+		{"в пределах", plotter.XY{X: 0, Y: 0}, plotter.XY{X: 10, Y: 7}, plotter.XY{X: 0, Y: 0}, plotter.XY{X: 10, Y: 7}},
+		{"вытянута по горизонтали", plotter.XY{X: 0, Y: 0}, plotter.XY{X: 10, Y: 1}, plotter.XY{X: 0, Y: -2}, plotter.XY{X: 10, Y: 3}},
+		{"вытянута по вертикали", plotter.XY{X: 0, Y: 0}, plotter.XY{X: 2, Y: 10}, plotter.XY{X: -4, Y: 0}, plotter.XY{X: 6, Y: 10}},
+	}
+	for _, tt := range tests {
+		low, high := padToAspect(tt.low, tt.high)
+		if low != tt.wantLow || high != tt.wantHigh {
+			t.Errorf("%s: [%v; %v], ожидалось [%v; %v]", tt.name, low, high, tt.wantLow, tt.wantHigh)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Функция для записи маршрута в виде "1 → 2 → 4"
+func formatPath(vertices []string, path []int) string {
+	names := make([]string, len(path))
+	for i, v := range path {
+		names[i] = vertices[v]
+	}
+	return strings.Join(names, " → ")
+}
+
+// Функция для нахождения вершины, путь из которой в to самый долгий.
+// Максимум берётся по столбцу to, как и во внешнем радиусе.
+func farthestVertex(distMatrix DistanceMatrix, to int) int {
+	farthest := to
+	for i := range distMatrix.Dist {
+		if distMatrix.Dist[i][to] > distMatrix.Dist[farthest][to] {
+			farthest = i
+		}
+	}
+	return farthest
+}
+
+// Функция для построения таблицы участков маршрута из from в to:
+// каждая строка — один перегон с его временем и временем с начала пути
+func routeLegsTable(graph *Graph, distMatrix DistanceMatrix, from, to int) [][]string {
+	table := [][]string{{"Участок", "Откуда", "Куда", "Время", "Время с начала"}}
+	path := distMatrix.Path(from, to)
+	if path == nil {
+		return append(table, []string{"—", graph.Vertices[from], graph.Vertices[to], "∞", "∞"})
+	}
+
+	elapsed := 0.0
+	for i := 1; i < len(path); i++ {
+		weight, _ := graph.Edge(path[i-1], path[i])
+		elapsed += weight
+		table = append(table, []string{
+			fmt.Sprintf("%d", i),
+			graph.Vertices[path[i-1]],
+			graph.Vertices[path[i]],
+			fmt.Sprintf("%.2f", weight),
+			fmt.Sprintf("%.2f", elapsed),
+		})
+	}
+	return table
+}
+
+// Функция для построения таблицы кратчайших маршрутов между всеми парами вершин
+func allRoutesTable(distMatrix DistanceMatrix) [][]string {
+	table := [][]string{{"Откуда", "Куда", "Маршрут", "Время"}}
+	for i, from := range distMatrix.Vertices {
+		for j, to := range distMatrix.Vertices {
+			if i == j {
+				continue
+			}
+			route := "нет пути"
+			if path := distMatrix.Path(i, j); path != nil {
+				route = formatPath(distMatrix.Vertices, path)
+			}
+			table = append(table, []string{from, to, route, formatDistance(distMatrix.Dist[i][j])})
+		}
+	}
+	return table
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+)
+
+// Число точек, в которых вычисляется ядерная оценка плотности для «скрипки»
+const violinPoints = 60
+
+// Функция для ядерной оценки плотности с гауссовым ядром и шириной окна
+// по правилу Сильвермана. Возвращает точки (значение, плотность) на
+// отрезке от минимума до максимума выборки.
+func kernelDensity(sorted []float64) plotter.XYs {
+	n := float64(len(sorted))
+	low, high := sorted[0], sorted[len(sorted)-1]
+	if high == low {
+		return plotter.XYs{{X: low, Y: 1}}
+	}
+	mean := meanOf(sorted)
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+	sd := math.Sqrt(variance / math.Max(n-1, 1))
+	iqr := empiricalQuantile(sorted, 0.75) - empiricalQuantile(sorted, 0.25)
+	spread := sd
+	if iqr > 0 {
+		spread = math.Min(sd, iqr/1.34)
+	}
+	bandwidth := 0.9 * math.Max(spread, (high-low)/100) * math.Pow(n, -0.2)
+
+	density := make(plotter.XYs, violinPoints)
+	for k := range density {
+		x := low + (high-low)*float64(k)/float64(violinPoints-1)
+		sum := 0.0
+		// Вклад точек дальше 4 ширин окна пренебрежимо мал
+		from, _ := slices.BinarySearch(sorted, x-4*bandwidth)
+		for _, v := range sorted[from:] {
+			if v > x+4*bandwidth {
+				break
+			}
+			z := (x - v) / bandwidth
+			sum += math.Exp(-z * z / 2)
+		}
+		density[k] = plotter.XY{X: x, Y: sum / (n * bandwidth * math.Sqrt(2*math.Pi))}
+	}
+	return density
+}
+
+// Функция для построения «скрипок» и диаграмм размаха радиусов всех вершин
+func radiusViolinPlot(points []string, radii [][]float64, title string) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "Номер вершины"
+	p.Y.Label.Text = "Время проезда"
+
+	const halfWidth = 0.4
+	for i, samples := range radii {
+		if len(samples) == 0 {
+			continue
+		}
+		sorted := slices.Clone(samples)
+		slices.Sort(sorted)
+
+		density := kernelDensity(sorted)
+		peak := 0.0
+		for _, point := range density {
+			peak = math.Max(peak, point.Y)
+		}
+		// Контур: правая половина снизу вверх, левая — сверху вниз
+		var outline plotter.XYs
+		for _, point := range density {
+			outline = append(outline, plotter.XY{X: float64(i) + halfWidth*point.Y/peak, Y: point.X})
+		}
+		for k := len(density) - 1; k >= 0; k-- {
+			outline = append(outline, plotter.XY{X: float64(i) - halfWidth*density[k].Y/peak, Y: density[k].X})
+		}
+		violin, err := plotter.NewPolygon(outline)
+		if err != nil {
+			return nil, err
+		}
+		violin.Color = color.NRGBA{R: 70, G: 130, B: 180, A: 80}
+		violin.LineStyle.Color = color.RGBA{R: 70, G: 130, B: 180, A: 255}
+
+		box, err := plotter.NewBoxPlot(vg.Points(10), float64(i), plotter.Values(sorted))
+		if err != nil {
+			return nil, err
+		}
+		box.FillColor = color.White
+		p.Add(violin, box)
+	}
+	p.NominalX(points...)
+	return p, nil
+}
+
+// Функция для построения эмпирических функций распределения радиусов всех
+// вершин на одном графике
+func radiusCDFPlot(points []string, radii [][]float64, title string) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "Время проезда"
+	p.Y.Label.Text = "F(x)"
+
+	for i, samples := range radii {
+		if len(samples) == 0 {
+			continue
+		}
+		sorted := slices.Clone(samples)
+		slices.Sort(sorted)
+		n := float64(len(sorted))
+		var steps plotter.XYs
+		for k, v := range sorted {
+			// Одинаковые значения дают одну ступеньку
+			if k+1 < len(sorted) && sorted[k+1] == v {
+				continue
+			}
+			if len(steps) > 0 {
+				steps = append(steps, plotter.XY{X: v, Y: steps[len(steps)-1].Y})
+			}
+			steps = append(steps, plotter.XY{X: v, Y: float64(k+1) / n})
+		}
+		line, err := plotter.NewLine(steps)
+		if err != nil {
+			return nil, err
+		}
+		line.Color = plotutil.Color(i)
+		line.Dashes = plotutil.Dashes(i / len(plotutil.DefaultColors))
+		line.Width = vg.Points(1.5)
+		p.Add(line)
+		p.Legend.Add(points[i], line)
+	}
+	p.Legend.Top, p.Legend.Left = true, true
+	p.Y.Min, p.Y.Max = 0, 1.05
+	return p, nil
+}
+
+// Функция для построения распределений внешнего и внутреннего радиусов
+// вершин по всем смоделированным связным сетям: «скрипки» с диаграммами
+// размаха в одном рисунке и функции распределения в другом
+func createRadiusPlots(points []string, simulation placementSimulation, violinFile, cdfFile string) error {
+	external, err := radiusViolinPlot(points, simulation.externalRadii, "Внешний радиус")
+	if err != nil {
+		return err
+	}
+	internal, err := radiusViolinPlot(points, simulation.internalRadii, "Внутренний радиус")
+	if err != nil {
+		return err
+	}
+	if err := savePlotGrid([][]*plot.Plot{{external, internal}}, 14*vg.Inch, 5*vg.Inch, violinFile); err != nil {
+		return err
+	}
+
+	external, err = radiusCDFPlot(points, simulation.externalRadii, "Внешний радиус")
+	if err != nil {
+		return err
+	}
+	internal, err = radiusCDFPlot(points, simulation.internalRadii, "Внутренний радиус")
+	if err != nil {
+		return err
+	}
+	return savePlotGrid([][]*plot.Plot{{external, internal}}, 14*vg.Inch, 5*vg.Inch, cdfFile)
+}
+
+// Функция для построения таблицы распределений радиусов: медиана
+// и квартили радиусов, а также отставание значения критерия вершины
+// от лучшей вершины той же сети
+func radiusDistributionTable(points []string, simulation placementSimulation) [][]string {
+	table := [][]string{{
+		"Вершина",
+		"Внешний радиус: медиана [Q1; Q3]", "Внутренний радиус: медиана [Q1; Q3]",
+		"Среднее отставание от лучшей", "Медиана отставания", "Доля сетей без отставания",
+	}}
+	quartiles := func(samples []float64) string {
+		if len(samples) == 0 {
+			return "—"
+		}
+		sorted := slices.Clone(samples)
+		slices.Sort(sorted)
+		return fmt.Sprintf("%.2f [%.2f; %.2f]", empiricalQuantile(sorted, 0.5), empiricalQuantile(sorted, 0.25), empiricalQuantile(sorted, 0.75))
+	}
+
+	values := eccentricityValues(simulation.externalRadii, simulation.internalRadii)
+	scenarios := 0
+	if len(values) > 0 {
+		scenarios = len(values[0])
+	}
+	best := make([]float64, scenarios)
+	for s := range best {
+		best[s] = math.Inf(1)
+		for i := range values {
+			best[s] = math.Min(best[s], values[i][s])
+		}
+	}
+
+	for i, vertex := range points {
+		regrets := make([]float64, scenarios)
+		zero := 0
+		for s := range regrets {
+			regrets[s] = values[i][s] - best[s]
+			if regrets[s] == 0 {
+				zero++
+			}
+		}
+		slices.Sort(regrets)
+		row := []string{vertex, quartiles(simulation.externalRadii[i]), quartiles(simulation.internalRadii[i]), "—", "—", "—"}
+		if scenarios > 0 {
+			row[3] = fmt.Sprintf("%.2f", meanOf(regrets))
+			row[4] = fmt.Sprintf("%.2f", empiricalQuantile(regrets, 0.5))
+			row[5] = fmt.Sprintf("%.4f", float64(zero)/float64(scenarios))
+		}
+		table = append(table, row)
+	}
+	return table
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Способы встраивания изображений в HTML-отчёт
+const (
+	embedNone = "none" // ссылки на файлы рядом с отчётом
+	embedPNG  = "png"  // изображения в base64 внутри атрибута src
+	embedSVG  = "svg"  // графики — встроенный SVG, прочие изображения — base64
+)
+
+var embedModes = []string{embedNone, embedPNG, embedSVG}
+
+// reportCell — ячейка таблицы отчёта. Выделение хранится признаком,
+// а не разметкой в тексте, чтобы текст можно было экранировать.
+type reportCell struct {
+	Text      string
+	Highlight bool
+}
+
+// reportTable — таблица отчёта с заголовком
+type reportTable struct {
+	Header []string
+	Rows   [][]reportCell
+}
+
+// Функция для создания таблицы отчёта из строк; первая строка — заголовок
+func tableFromRows(rows [][]string) reportTable {
+	var table reportTable
+	if len(rows) == 0 {
+		return table
+	}
+	table.Header = rows[0]
+	for _, row := range rows[1:] {
+		cells := make([]reportCell, len(row))
+		for j, text := range row {
+			cells[j] = reportCell{Text: text}
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	return table
+}
+
+// highlightRow выделяет строку данных i (без учёта заголовка)
+func (t reportTable) highlightRow(i int) {
+	for j := range t.Rows[i] {
+		t.Rows[i][j].Highlight = true
+	}
+}
+
+// highlightCell выделяет ячейку j строки данных i
+func (t reportTable) highlightCell(i, j int) {
+	t.Rows[i][j].Highlight = true
+}
+
+// reportFigure — изображение в отчёте. Source и Inline заполняются
+// при записи в зависимости от способа встраивания.
+type reportFigure struct {
+	File    string
+	Caption string // подпись под изображением
+	Source  template.URL
+	Inline  template.HTML
+}
+
+// reportChart — данные столбчатой диаграммы для интерактивного отчёта
+type reportChart struct {
+	Labels   []string
+	Values   []float64
+	Tooltips []string // подсказка к каждому столбцу
+	XLabel   string
+	YLabel   string
+}
+
+// reportSection — раздел отчёта: таблица, изображение, диаграмма, текст
+// или предупреждение. Разделы без заголовка не попадают в оглавление.
+// Разделы одной группы Group в интерактивном отчёте переключаются вкладками.
+type reportSection struct {
+	ID      string
+	Title   string
+	Table   *reportTable
+	Figure  *reportFigure
+	Chart   *reportChart
+	Group   string
+	Text    string
+	Warning bool
+}
+
+// report — модель отчёта; разделы накапливаются по ходу расчёта,
+// а файл записывается один раз в конце
+type report struct {
+	Title       string
+	Sections    []reportSection
+	Interactive bool
+}
+
+// htmlOptions — параметры записи HTML-отчёта
+type htmlOptions struct {
+	embed       string // способ встраивания изображений
+	interactive bool   // сортировка и фильтр таблиц, интерактивные диаграммы, вкладки
+}
+
+func newReport(title string) *report {
+	return &report{Title: title}
+}
+
+func (r *report) add(section reportSection) {
+	if section.Title != "" {
+		section.ID = fmt.Sprintf("section-%d", len(r.Sections)+1)
+	}
+	r.Sections = append(r.Sections, section)
+}
+
+// addTable добавляет раздел с таблицей
+func (r *report) addTable(title string, table reportTable) {
+	r.add(reportSection{Title: title, Table: &table})
+}
+
+// addRows добавляет раздел с таблицей из строк без выделения
+func (r *report) addRows(title string, rows [][]string) {
+	r.addTable(title, tableFromRows(rows))
+}
+
+// addFigure добавляет раздел с изображением
+func (r *report) addFigure(title, filename string) {
+	r.add(reportSection{Title: title, Figure: &reportFigure{File: filename}})
+	log.Printf("Added image %s to report with title %s", filename, title)
+}
+
+// addSubfigure добавляет изображение с подписью к предыдущему разделу:
+// у него нет заголовка, поэтому в оглавлении оно не появляется
+func (r *report) addSubfigure(caption, filename string) {
+	r.add(reportSection{Figure: &reportFigure{File: filename, Caption: caption}})
+	log.Printf("Added image %s to report with caption %s", filename, caption)
+}
+
+// addChart добавляет раздел с изображением и данными диаграммы: в интерактивном
+// отчёте вместо изображения выводится диаграмма с подсказками. Без изображения
+// (filename == "") раздел есть только в интерактивном отчёте.
+func (r *report) addChart(title, filename string, chart reportChart, group string) {
+	section := reportSection{Title: title, Chart: &chart, Group: group}
+	if filename != "" {
+		section.Figure = &reportFigure{File: filename}
+	}
+	r.add(section)
+}
+
+// addText добавляет раздел с текстом
+func (r *report) addText(title, text string) {
+	r.add(reportSection{Title: title, Text: text})
+}
+
+// addWarning добавляет предупреждение
+func (r *report) addWarning(message string) {
+	r.add(reportSection{Text: message, Warning: true})
+}
+
+// Функция для записи отчёта в HTML-файл. При встраивании изображений
+// отчёт не зависит от соседних файлов.
+func (r *report) writeHTML(filename string, options htmlOptions) error {
+	resolved := &report{Title: r.Title, Interactive: options.interactive}
+	for _, section := range r.Sections {
+		if section.chartOnly() && !options.interactive {
+			continue
+		}
+		if section.Figure != nil && !(options.interactive && section.Chart != nil) {
+			figure, err := resolveFigure(section.Figure.File, options.embed)
+			if err != nil {
+				return err
+			}
+			figure.Caption = section.Figure.Caption
+			section.Figure = &figure
+		}
+		resolved.Sections = append(resolved.Sections, section)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := reportTemplate.Execute(file, resolved); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Функция для подготовки изображения к выводу выбранным способом
+func resolveFigure(filename, embed string) (reportFigure, error) {
+	figure := reportFigure{File: filename}
+	if embed == embedSVG {
+		if svg, err := os.ReadFile(svgFilename(filename)); err == nil {
+			// XML-пролог недопустим внутри HTML
+			if start := bytes.Index(svg, []byte("<svg")); start >= 0 {
+				svg = svg[start:]
+			}
+			figure.Inline = template.HTML(svg)
+			return figure, nil
+		}
+	}
+	if embed == embedNone {
+		figure.Source = template.URL(filename)
+		return figure, nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return figure, fmt.Errorf("не удалось встроить изображение: %v", err)
+	}
+	mediaType := mime.TypeByExtension(filepath.Ext(filename))
+	if mediaType == "" {
+		mediaType = "image/png"
+	}
+	figure.Source = template.URL("data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data))
+	return figure, nil
+}
+
+// Функция для получения имени SVG-копии графика
+func svgFilename(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ".svg"
+}
+
+// Функция для сохранения графика. При встраивании SVG рядом сохраняется
+// векторная копия, которая попадёт в отчёт вместо растрового файла.
+func savePlot(p *plot.Plot, width, height vg.Length, filename string) error {
+	if err := p.Save(width, height, filename); err != nil {
+		return err
+	}
+	if *embedFlag == embedSVG {
+		return p.Save(width, height, svgFilename(filename))
+	}
+	return nil
+}
+
+// Функция для сохранения нескольких графиков одним рисунком. Как и savePlot,
+// при встраивании SVG рядом сохраняет векторную копию.
+func savePlotGrid(plots [][]*plot.Plot, width, height vg.Length, filename string) error {
+	formats := []string{filename}
+	if *embedFlag == embedSVG {
+		formats = append(formats, svgFilename(filename))
+	}
+	for _, name := range formats {
+		canvas, err := draw.NewFormattedCanvas(width, height, strings.TrimPrefix(filepath.Ext(name), "."))
+		if err != nil {
+			return err
+		}
+		tiles := draw.Tiles{Rows: len(plots), Cols: len(plots[0]), PadX: vg.Points(10), PadY: vg.Points(10)}
+		canvases := plot.Align(plots, tiles, draw.New(canvas))
+		for i := range plots {
+			for j := range plots[i] {
+				plots[i][j].Draw(canvases[i][j])
+			}
+		}
+
+		file, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := canvas.WriteTo(file); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>{{.Title}}</title>
+	<style>
+		body { font-family: "Segoe UI", Arial, sans-serif; margin: 2em auto; max-width: 1200px; padding: 0 1em; color: #222; }
+		h1 { border-bottom: 2px solid #4682b4; padding-bottom: 0.3em; }
+		h2 { margin-top: 2em; color: #2f4f6f; }
+		nav ol { columns: 2; font-size: 0.95em; }
+		nav a { color: #2f4f6f; text-decoration: none; }
+		nav a:hover { text-decoration: underline; }
+		.table-wrap { overflow-x: auto; }
+		table { border-collapse: collapse; font-size: 0.9em; }
+		th, td { border: 1px solid #c8d1da; padding: 0.3em 0.6em; text-align: right; }
+		th { background: #e8eef4; position: sticky; top: 0; }
+		td:first-child, th:first-child { text-align: left; }
+		tr:nth-child(even) td { background: #f7f9fb; }
+		td.highlight { font-weight: bold; background: #fff3c4; }
+		figure { margin: 0; }
+		figcaption { color: #555; font-style: italic; }
+		figure img, figure svg { max-width: 100%; height: auto; }
+		.warning { color: #b00020; border-left: 4px solid #b00020; padding: 0.5em 1em; background: #fdecee; }
+	</style>
+{{- if .Interactive}}
+	<style>{{.Styles}}</style>
+	<script>{{.Script}}</script>
+{{- end}}
+</head>
+<body>
+	<h1>{{.Title}}</h1>
+	<nav>
+		<h2>Содержание</h2>
+		<ol>
+		{{- range .Sections}}{{if .ID}}
+			<li><a href="#{{.ID}}">{{.Title}}</a></li>
+		{{- end}}{{end}}
+		</ol>
+	</nav>
+{{- range .Sections}}
+	<section{{if .ID}} id="{{.ID}}"{{end}}{{if and $.Interactive .Group}} data-group="{{.Group}}"{{end}}>
+	{{- if .Title}}
+		<h2>{{.Title}}</h2>
+	{{- end}}
+	{{- if .Warning}}
+		<p class="warning"><b>Внимание:</b> {{.Text}}</p>
+	{{- else if .Text}}
+		<p>{{.Text}}</p>
+	{{- end}}
+	{{- with .Table}}
+		<div class="table-wrap">
+		<table>
+			<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+			{{- range .Rows}}
+			<tr>{{range .}}<td{{if .Highlight}} class="highlight"{{end}}>{{.Text}}</td>{{end}}</tr>
+			{{- end}}
+		</table>
+		</div>
+	{{- end}}
+	{{- if and $.Interactive .Chart}}
+		<div class="chart" id="chart-{{.ID}}"></div>
+		<script>renderChart("chart-{{.ID}}", {{.Chart}});</script>
+	{{- else if .Figure}}
+		<figure>
+		{{- if .Figure.Inline}}
+			{{.Figure.Inline}}
+		{{- else}}
+			<img src="{{.Figure.Source}}" alt="{{or .Title .Figure.Caption}}">
+		{{- end}}
+		{{- if .Figure.Caption}}
+			<figcaption>{{.Figure.Caption}}</figcaption>
+		{{- end}}
+		</figure>
+	{{- end}}
+	</section>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-fonts/liberation/liberationsansbold"
+	"github.com/go-fonts/liberation/liberationsansregular"
+	"github.com/go-pdf/fpdf"
+)
+
+// Дополнительные форматы отчёта; HTML записывается всегда
+const (
+	reportMarkdown = "md"
+	reportPDF      = "pdf"
+)
+
+var reportFormats = []string{reportMarkdown, reportPDF}
+
+// Функция для получения форматов отчёта, перечисленных в -report-formats
+func selectedReportFormats() []string {
+	var formats []string
+	for _, format := range strings.Split(*formatsFlag, ",") {
+		if format = strings.TrimSpace(format); format != "" {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// chartOnly сообщает, что раздел есть только в интерактивном HTML-отчёте
+func (s reportSection) chartOnly() bool {
+	return s.Chart != nil && s.Figure == nil
+}
+
+// Функция для записи отчёта в Markdown: таблицы в разметке GitHub,
+// выделенные ячейки полужирные, изображения — ссылки на файлы рядом с отчётом
+func (r *report) writeMarkdown(filename string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n## Содержание\n\n", r.Title)
+	for _, section := range r.Sections {
+		if section.ID != "" && !section.chartOnly() {
+			fmt.Fprintf(&b, "- [%s](#%s)\n", markdownEscape(section.Title), section.ID)
+		}
+	}
+
+	for _, section := range r.Sections {
+		if section.chartOnly() {
+			continue
+		}
+		b.WriteString("\n")
+		if section.Title != "" {
+			fmt.Fprintf(&b, "<a id=\"%s\"></a>\n\n## %s\n\n", section.ID, markdownEscape(section.Title))
+		}
+		if section.Warning {
+			fmt.Fprintf(&b, "> **Внимание:** %s\n\n", markdownEscape(section.Text))
+		} else if section.Text != "" {
+			fmt.Fprintf(&b, "%s\n\n", markdownEscape(section.Text))
+		}
+		if section.Table != nil {
+			writeMarkdownTable(&b, *section.Table)
+		}
+		if section.Figure != nil {
+			if caption := section.Figure.Caption; caption != "" {
+				fmt.Fprintf(&b, "![%s](%s)\n\n*%s*\n\n", markdownEscape(caption), section.Figure.File, markdownEscape(caption))
+			} else {
+				fmt.Fprintf(&b, "![%s](%s)\n\n", markdownEscape(section.Title), section.Figure.File)
+			}
+		}
+	}
+	return os.WriteFile(filename, []byte(b.String()), 0o644)
+}
+
+// Функция для записи таблицы в разметке GitHub; недостающие ячейки строк пустые
+func writeMarkdownTable(b *strings.Builder, table reportTable) {
+	if len(table.Header) == 0 {
+		return
+	}
+	columns := len(table.Header)
+	for _, row := range table.Rows {
+		columns = max(columns, len(row))
+	}
+	cells := make([]string, columns)
+	for j := range cells {
+		cells[j] = ""
+		if j < len(table.Header) {
+			cells[j] = markdownCell(table.Header[j])
+		}
+	}
+	fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	for j := range cells {
+		cells[j] = "---"
+	}
+	fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	for _, row := range table.Rows {
+		for j := range cells {
+			cells[j] = ""
+			if j < len(row) {
+				cells[j] = markdownCell(row[j].Text)
+				if row[j].Highlight && cells[j] != "" {
+					cells[j] = "**" + cells[j] + "**"
+				}
+			}
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	}
+	b.WriteString("\n")
+}
+
+// Функция для экранирования символов разметки Markdown в тексте
+func markdownEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// Функция для подготовки текста ячейки таблицы Markdown
+func markdownCell(text string) string {
+	return strings.ReplaceAll(markdownEscape(strings.Join(strings.Fields(text), " ")), "|", `\|`)
+}
+
+// Параметры вёрстки PDF-отчёта, миллиметры и пункты
+const (
+	pdfMargin       = 15.0
+	pdfCellPadding  = 1.5
+	pdfTableFont    = 8.0
+	pdfMinTableFont = 5.0
+	pdfMaxColumn    = 70.0 // наибольшая ширина столбца; более длинный текст переносится
+)
+
+// Функция для записи отчёта в PDF: разделы с закладками, таблицы
+// с повтором заголовка на каждой странице (слишком широкие разбиваются
+// на части по столбцам с повтором первого столбца), изображения — PNG.
+// Шрифт Liberation Sans встраивается, чтобы кириллица отображалась везде.
+func (r *report) writePDF(filename string) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("LiberationSans", "", liberationsansregular.TTF)
+	pdf.AddUTF8FontFromBytes("LiberationSans", "B", liberationsansbold.TTF)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle(r.Title, true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin + 5)
+		pdf.SetFont("LiberationSans", "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pdf.SetFont("LiberationSans", "B", 18)
+	pdf.MultiCell(0, 9, r.Title, "", "L", false)
+	pdf.Ln(4)
+
+	for _, section := range r.Sections {
+		if section.chartOnly() {
+			continue
+		}
+		if section.Title != "" {
+			// Заголовок не должен оставаться внизу страницы без содержимого
+			_, pageHeight := pdf.GetPageSize()
+			if pdf.GetY() > pageHeight-pdfMargin-30 {
+				pdf.AddPage()
+			}
+			pdf.Ln(3)
+			pdf.Bookmark(section.Title, 0, -1)
+			pdf.SetFont("LiberationSans", "B", 12)
+			pdf.SetTextColor(47, 79, 111)
+			pdf.MultiCell(0, 6, section.Title, "", "L", false)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.Ln(1)
+		}
+		if section.Text != "" {
+			pdf.SetFont("LiberationSans", "", 10)
+			text := section.Text
+			if section.Warning {
+				pdf.SetTextColor(176, 0, 32)
+				text = "Внимание: " + text
+			}
+			pdf.MultiCell(0, 5, text, "", "L", false)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.Ln(1)
+		}
+		if section.Table != nil {
+			writePDFTable(pdf, *section.Table)
+		}
+		if section.Figure != nil {
+			writePDFImage(pdf, section.Figure.File)
+			if section.Figure.Caption != "" {
+				pdf.SetFont("LiberationSans", "", 9)
+				pdf.MultiCell(0, 5, section.Figure.Caption, "", "C", false)
+				pdf.Ln(1)
+			}
+		}
+		if err := pdf.Error(); err != nil {
+			return fmt.Errorf("раздел %q: %v", section.Title, err)
+		}
+	}
+	return pdf.OutputFileAndClose(filename)
+}
+
+// Функция для вывода таблицы в PDF
+func writePDFTable(pdf *fpdf.Fpdf, table reportTable) {
+	if len(table.Header) == 0 {
+		return
+	}
+	pageWidth, _ := pdf.GetPageSize()
+	available := pageWidth - 2*pdfMargin
+	columns := len(table.Header)
+	for _, row := range table.Rows {
+		columns = max(columns, len(row))
+	}
+	// Ячейка строки данных i или заголовка (i < 0)
+	cell := func(i, j int) reportCell {
+		switch {
+		case i < 0 && j < len(table.Header):
+			return reportCell{Text: table.Header[j]}
+		case i >= 0 && j < len(table.Rows[i]):
+			return table.Rows[i][j]
+		}
+		return reportCell{}
+	}
+
+	// Ширина столбцов по самому длинному тексту; если таблица не помещается,
+	// шрифт уменьшается, а затем столбцы делятся на части
+	fontSize := pdfTableFont
+	var widths []float64
+	for {
+		pdf.SetFont("LiberationSans", "", fontSize)
+		widths = make([]float64, columns)
+		total := 0.0
+		for j := range widths {
+			for i := -1; i < len(table.Rows); i++ {
+				widths[j] = max(widths[j], pdf.GetStringWidth(cell(i, j).Text)+2*pdfCellPadding)
+			}
+			widths[j] = min(widths[j], pdfMaxColumn)
+			total += widths[j]
+		}
+		if total <= available || fontSize <= pdfMinTableFont {
+			break
+		}
+		fontSize--
+	}
+
+	var groups [][]int
+	group, used := []int{0}, widths[0]
+	for j := 1; j < columns; j++ {
+		if used+widths[j] > available && len(group) > 1 {
+			groups = append(groups, group)
+			group, used = []int{0}, widths[0]
+		}
+		group = append(group, j)
+		used += widths[j]
+	}
+	groups = append(groups, group)
+
+	layout := pdfTableLayout{cell: cell, widths: widths, fontSize: fontSize, lineHeight: fontSize * 0.45}
+	for part, group := range groups {
+		if len(groups) > 1 {
+			pdf.SetFont("LiberationSans", "", 8)
+			pdf.CellFormat(0, 5, fmt.Sprintf("Часть %d из %d", part+1, len(groups)), "", 1, "L", false, 0, "")
+		}
+		layout.columns = group
+		layout.row(pdf, -1)
+		for i := range table.Rows {
+			layout.row(pdf, i)
+		}
+		pdf.Ln(3)
+	}
+}
+
+// pdfTableLayout — вёрстка части таблицы PDF: выводимые столбцы и их ширины
+type pdfTableLayout struct {
+	cell       func(i, j int) reportCell
+	columns    []int
+	widths     []float64
+	fontSize   float64
+	lineHeight float64
+}
+
+// row выводит строку данных i (i < 0 — строку заголовка). Текст длиннее
+// столбца переносится, высота строки — по самой высокой ячейке. Если строка
+// не помещается на странице, на новой странице повторяется заголовок.
+func (l pdfTableLayout) row(pdf *fpdf.Fpdf, i int) {
+	style := ""
+	if i < 0 {
+		style = "B"
+	}
+	pdf.SetFont("LiberationSans", style, l.fontSize)
+
+	lines := make([][]string, len(l.columns))
+	height := l.lineHeight
+	for k, j := range l.columns {
+		lines[k] = pdf.SplitText(l.cell(i, j).Text, l.widths[j]-2*pdfCellPadding)
+		height = max(height, float64(len(lines[k]))*l.lineHeight)
+	}
+	height += pdfCellPadding
+
+	_, pageHeight := pdf.GetPageSize()
+	if i >= 0 && pdf.GetY()+height > pageHeight-pdfMargin {
+		pdf.AddPage()
+		l.row(pdf, -1)
+		pdf.SetFont("LiberationSans", style, l.fontSize)
+	}
+
+	x, y := pdf.GetXY()
+	pdf.SetDrawColor(200, 209, 218)
+	for k, j := range l.columns {
+		switch {
+		case i < 0:
+			pdf.SetFillColor(232, 238, 244)
+		case l.cell(i, j).Highlight:
+			pdf.SetFillColor(255, 243, 196)
+		default:
+			pdf.SetFillColor(255, 255, 255)
+		}
+		pdf.Rect(x, y, l.widths[j], height, "FD")
+		align := "R"
+		if j == 0 {
+			align = "L"
+		}
+		for n, line := range lines[k] {
+			pdf.SetXY(x+pdfCellPadding, y+pdfCellPadding/2+float64(n)*l.lineHeight)
+			pdf.CellFormat(l.widths[j]-2*pdfCellPadding, l.lineHeight, line, "", 0, align, false, 0, "")
+		}
+		x += l.widths[j]
+	}
+	pdf.SetXY(pdfMargin, y+height)
+}
+
+// Функция для вывода изображения в PDF по ширине страницы; высокое
+// изображение уменьшается до высоты страницы
+func writePDFImage(pdf *fpdf.Fpdf, filename string) {
+	info := pdf.RegisterImageOptions(filename, fpdf.ImageOptions{ReadDpi: true})
+	if info == nil {
+		return
+	}
+	pageWidth, pageHeight := pdf.GetPageSize()
+	width := pageWidth - 2*pdfMargin
+	height := width * info.Height() / info.Width()
+	if limit := pageHeight - 2*pdfMargin - 10; height > limit {
+		width, height = width*limit/height, limit
+	}
+	if pdf.GetY()+height > pageHeight-pdfMargin {
+		pdf.AddPage()
+	}
+	pdf.ImageOptions(filename, pdfMargin, pdf.GetY(), width, height, true, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+	pdf.Ln(3)
+}
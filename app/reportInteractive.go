@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+)
+
+// Функция для вычисления 95% доверительного интервала Уилсона для доли count из n
+func wilsonInterval(count, n int) (float64, float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	const z = 1.96
+	p := float64(count) / float64(n)
+	total := float64(n)
+	denominator := 1 + z*z/total
+	center := (p + z*z/(2*total)) / denominator
+	half := z * math.Sqrt(p*(1-p)/total+z*z/(4*total*total)) / denominator
+	return math.Max(center-half, 0), math.Min(center+half, 1)
+}
+
+// Функция для построения подсказки к столбцу с числом итераций и долей
+func frequencyTooltip(label string, count, iterations int) string {
+	low, high := wilsonInterval(count, iterations)
+	share := 0.0
+	if iterations > 0 {
+		share = float64(count) / float64(iterations)
+	}
+	return fmt.Sprintf("%s: %d из %d итераций, доля %.4f, 95%% ДИ [%.4f; %.4f]", label, count, iterations, share, low, high)
+}
+
+// Styles возвращает стили интерактивного отчёта
+func (r *report) Styles() template.CSS {
+	return `
+		.table-filter { margin: 0.3em 0; padding: 0.2em 0.4em; width: 20em; }
+		th.sortable { cursor: pointer; user-select: none; }
+		th.sortable::after { content: " ⇅"; color: #8a9aaa; }
+		th.sorted-asc::after { content: " ▲"; color: #2f4f6f; }
+		th.sorted-desc::after { content: " ▼"; color: #2f4f6f; }
+		.chart-controls button, .tabs button { margin-right: 0.3em; padding: 0.2em 0.7em; border: 1px solid #c8d1da; background: #f7f9fb; cursor: pointer; }
+		.tabs button.active { background: #4682b4; color: #fff; }
+		.chart-scroll { overflow-x: auto; border: 1px solid #e8eef4; }
+		.chart-scroll rect.bar { fill: #4682b4; }
+		.chart-scroll rect.bar:hover { fill: #cd5c5c; }
+		.chart-tip { position: absolute; display: none; padding: 0.3em 0.6em; background: #222; color: #fff; font-size: 0.85em; border-radius: 3px; pointer-events: none; max-width: 30em; }
+`
+}
+
+// Script возвращает сценарий интерактивного отчёта; внешние библиотеки
+// не используются, чтобы отчёт открывался без сети
+func (r *report) Script() template.JS {
+	return reportScript
+}
+
+const reportScript template.JS = `
+"use strict";
+var SVG = "http://www.w3.org/2000/svg";
+
+function svgElement(name, attributes) {
+	var element = document.createElementNS(SVG, name);
+	for (var key in attributes) {
+		element.setAttribute(key, attributes[key]);
+	}
+	return element;
+}
+
+function chartTip() {
+	var tip = document.getElementById("chart-tip");
+	if (!tip) {
+		tip = document.createElement("div");
+		tip.id = "chart-tip";
+		tip.className = "chart-tip";
+		document.body.appendChild(tip);
+	}
+	return tip;
+}
+
+// Столбчатая диаграмма с подсказками и масштабированием по горизонтали
+function renderChart(id, chart) {
+	var box = document.getElementById(id);
+	var zoom = 1;
+	var controls = document.createElement("div");
+	controls.className = "chart-controls";
+	var scroll = document.createElement("div");
+	scroll.className = "chart-scroll";
+	[["+", 1.5], ["−", 1 / 1.5], ["1:1", 0]].forEach(function (button) {
+		var element = document.createElement("button");
+		element.textContent = button[0];
+		element.addEventListener("click", function () {
+			zoom = button[1] ? Math.min(Math.max(zoom * button[1], 0.5), 8) : 1;
+			draw();
+		});
+		controls.appendChild(element);
+	});
+	box.appendChild(controls);
+	box.appendChild(scroll);
+
+	function draw() {
+		var n = chart.Labels.length;
+		var left = 70, bottom = 80, top = 15, height = 340;
+		var barWidth = Math.max(8, 40 * zoom);
+		var width = Math.max(left + n * barWidth + 20, 300);
+		var plotHeight = height - top - bottom;
+		var finite = chart.Values.filter(function (v) { return isFinite(v); });
+		var maximum = Math.max.apply(null, finite.concat([0])) || 1;
+
+		var svg = svgElement("svg", {width: width, height: height, viewBox: "0 0 " + width + " " + height});
+		svg.appendChild(svgElement("line", {x1: left, y1: top, x2: left, y2: top + plotHeight, stroke: "#555"}));
+		svg.appendChild(svgElement("line", {x1: left, y1: top + plotHeight, x2: width - 10, y2: top + plotHeight, stroke: "#555"}));
+		for (var t = 0; t <= 4; t++) {
+			var value = maximum * t / 4;
+			var y = top + plotHeight - plotHeight * t / 4;
+			svg.appendChild(svgElement("line", {x1: left - 4, y1: y, x2: width - 10, y2: y, stroke: "#e8eef4"}));
+			var tick = svgElement("text", {x: left - 6, y: y + 4, "text-anchor": "end", "font-size": 11});
+			tick.textContent = value.toPrecision(3);
+			svg.appendChild(tick);
+		}
+
+		var tip = chartTip();
+		chart.Values.forEach(function (v, i) {
+			var barHeight = isFinite(v) ? plotHeight * v / maximum : 0;
+			var x = left + i * barWidth + barWidth * 0.15;
+			var bar = svgElement("rect", {
+				"class": "bar", x: x, y: top + plotHeight - barHeight,
+				width: barWidth * 0.7, height: Math.max(barHeight, 1)
+			});
+			bar.addEventListener("mousemove", function (event) {
+				tip.textContent = chart.Tooltips[i];
+				tip.style.display = "block";
+				tip.style.left = (event.pageX + 12) + "px";
+				tip.style.top = (event.pageY + 12) + "px";
+			});
+			bar.addEventListener("mouseleave", function () { tip.style.display = "none"; });
+			svg.appendChild(bar);
+
+			var lx = left + i * barWidth + barWidth / 2, ly = top + plotHeight + 14;
+			var label = svgElement("text", {
+				x: lx, y: ly, "font-size": 11,
+				"text-anchor": chart.Labels[i].length > 3 ? "end" : "middle",
+				transform: chart.Labels[i].length > 3 ? "rotate(-40 " + lx + " " + ly + ")" : ""
+			});
+			label.textContent = chart.Labels[i];
+			svg.appendChild(label);
+		});
+
+		var xLabel = svgElement("text", {x: left + (width - left) / 2, y: height - 6, "text-anchor": "middle", "font-size": 12});
+		xLabel.textContent = chart.XLabel;
+		svg.appendChild(xLabel);
+		var yLabel = svgElement("text", {x: 14, y: top + plotHeight / 2, "text-anchor": "middle", "font-size": 12,
+			transform: "rotate(-90 14 " + (top + plotHeight / 2) + ")"});
+		yLabel.textContent = chart.YLabel;
+		svg.appendChild(yLabel);
+
+		scroll.replaceChildren(svg);
+	}
+	draw();
+}
+
+// Значение ячейки для сортировки: число, бесконечность или текст
+function cellValue(cell) {
+	var text = cell.textContent.trim();
+	if (text === "∞") {
+		return Infinity;
+	}
+	var number = parseFloat(text.replace(",", "."));
+	return isNaN(number) || !/^[-+]?[\d.,]/.test(text) ? text : number;
+}
+
+function compareValues(a, b) {
+	if (typeof a === "number" && typeof b === "number") {
+		return a === b ? 0 : (a < b ? -1 : 1);
+	}
+	if (typeof a === "number") {
+		return -1;
+	}
+	if (typeof b === "number") {
+		return 1;
+	}
+	return a.localeCompare(b, "ru");
+}
+
+// Сортировка по щелчку на заголовке столбца и фильтр строк по подстроке
+function enhanceTable(table) {
+	var rows = Array.prototype.slice.call(table.rows);
+	if (rows.length < 3) {
+		return;
+	}
+	var header = rows[0], body = rows[0].parentNode, data = rows.slice(1);
+
+	var filter = document.createElement("input");
+	filter.type = "search";
+	filter.className = "table-filter";
+	filter.placeholder = "Фильтр строк";
+	filter.addEventListener("input", function () {
+		var query = filter.value.trim().toLowerCase();
+		data.forEach(function (row) {
+			row.style.display = row.textContent.toLowerCase().indexOf(query) >= 0 ? "" : "none";
+		});
+	});
+	table.parentNode.parentNode.insertBefore(filter, table.parentNode);
+
+	Array.prototype.forEach.call(header.cells, function (th, column) {
+		th.classList.add("sortable");
+		th.addEventListener("click", function () {
+			var ascending = !th.classList.contains("sorted-asc");
+			Array.prototype.forEach.call(header.cells, function (other) {
+				other.classList.remove("sorted-asc", "sorted-desc");
+			});
+			th.classList.add(ascending ? "sorted-asc" : "sorted-desc");
+			data.sort(function (a, b) {
+				var result = compareValues(cellValue(a.cells[column] || a), cellValue(b.cells[column] || b));
+				return ascending ? result : -result;
+			});
+			data.forEach(function (row) { body.appendChild(row); });
+		});
+	});
+}
+
+// Разделы одной группы показываются по одному с переключением вкладками
+function groupSections() {
+	var groups = {};
+	document.querySelectorAll("section[data-group]").forEach(function (section) {
+		var name = section.getAttribute("data-group");
+		(groups[name] = groups[name] || []).push(section);
+	});
+	Object.keys(groups).forEach(function (name) {
+		var sections = groups[name];
+		if (sections.length < 2) {
+			return;
+		}
+		var tabs = document.createElement("div");
+		tabs.className = "tabs";
+		var buttons = sections.map(function (section) {
+			var button = document.createElement("button");
+			button.textContent = section.querySelector("h2").textContent;
+			button.addEventListener("click", function () { show(section); });
+			tabs.appendChild(button);
+			return button;
+		});
+		function show(active) {
+			sections.forEach(function (section, i) {
+				section.style.display = section === active ? "" : "none";
+				buttons[i].classList.toggle("active", section === active);
+			});
+		}
+		sections[0].parentNode.insertBefore(tabs, sections[0]);
+		sections.forEach(function (section) { section.showTab = function () { show(section); }; });
+		show(sections[0]);
+	});
+
+	function showHash() {
+		var target = document.getElementById(location.hash.slice(1));
+		if (target && target.showTab) {
+			target.showTab();
+		}
+	}
+	window.addEventListener("hashchange", showHash);
+	showHash();
+}
+
+document.addEventListener("DOMContentLoaded", function () {
+	document.querySelectorAll("section table").forEach(enhanceTable);
+	groupSections();
+});
+`
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Уровни квантилей и CVaR для оценки риска размещения
+const (
+	riskQuantile90 = 0.90
+	riskQuantile95 = 0.95
+	cvarLevel      = 0.95
+)
+
+// Показатели риска одной вершины по всем смоделированным сетям
+type vertexRisk struct {
+	mean      float64
+	p90, p95  float64
+	cvar      float64 // среднее по худшим (1 - cvarLevel) сетям
+	maxRegret float64 // наибольшее отставание от лучшей в той же сети вершины
+}
+
+// Функция для вычисления показателей риска по значениям критерия
+// values[вершина][сеть]
+func calculateVertexRisks(values [][]float64) []vertexRisk {
+	risks := make([]vertexRisk, len(values))
+	if len(values) == 0 || len(values[0]) == 0 {
+		return risks
+	}
+
+	scenarios := len(values[0])
+	best := make([]float64, scenarios)
+	for s := range best {
+		best[s] = math.Inf(1)
+		for i := range values {
+			best[s] = math.Min(best[s], values[i][s])
+		}
+	}
+
+	for i, samples := range values {
+		sorted := slices.Clone(samples)
+		slices.Sort(sorted)
+		risks[i] = vertexRisk{
+			mean: meanOf(sorted),
+			p90:  empiricalQuantile(sorted, riskQuantile90),
+			p95:  empiricalQuantile(sorted, riskQuantile95),
+			cvar: meanOf(sorted[int(math.Floor(cvarLevel*float64(len(sorted)))):]),
+		}
+		for s, v := range samples {
+			risks[i].maxRegret = math.Max(risks[i].maxRegret, v-best[s])
+		}
+	}
+	return risks
+}
+
+// Функция для вычисления эмпирического квантиля упорядоченной выборки
+func empiricalQuantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	index := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(index, 0)]
+}
+
+// Функция для вычисления среднего значения
+func meanOf(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Функция для построения таблиц сравнения вершин по критериям риска:
+// первая — показатели всех вершин с выделением лучшей по каждому критерию,
+// вторая — рекомендуемая вершина по каждому критерию
+func riskTables(vertices []string, risks []vertexRisk) (reportTable, [][]string) {
+	criteria := []struct {
+		name  string
+		value func(vertexRisk) float64
+	}{
+		{"Среднее", func(r vertexRisk) float64 { return r.mean }},
+		{"90-й процентиль", func(r vertexRisk) float64 { return r.p90 }},
+		{"95-й процентиль", func(r vertexRisk) float64 { return r.p95 }},
+		{fmt.Sprintf("CVaR %.0f%%", cvarLevel*100), func(r vertexRisk) float64 { return r.cvar }},
+		{"Наибольшее сожаление", func(r vertexRisk) float64 { return r.maxRegret }},
+	}
+
+	comparison := make([][]string, len(vertices)+1)
+	comparison[0] = []string{"Вершина"}
+	for i, v := range vertices {
+		comparison[i+1] = []string{v}
+	}
+	summary := [][]string{{"Критерий", "Рекомендуемая вершина", "Значение"}}
+
+	var best []int
+	for _, criterion := range criteria {
+		comparison[0] = append(comparison[0], criterion.name)
+		bestVertex := -1
+		for i, risk := range risks {
+			value := criterion.value(risk)
+			comparison[i+1] = append(comparison[i+1], fmt.Sprintf("%.2f", value))
+			if !math.IsNaN(value) && (bestVertex < 0 || value < criterion.value(risks[bestVertex])) {
+				bestVertex = i
+			}
+		}
+		best = append(best, bestVertex)
+		if bestVertex < 0 {
+			summary = append(summary, []string{criterion.name, "—", "—"})
+			continue
+		}
+		summary = append(summary, []string{criterion.name, vertices[bestVertex], fmt.Sprintf("%.2f", criterion.value(risks[bestVertex]))})
+	}
+
+	table := tableFromRows(comparison)
+	for k, bestVertex := range best {
+		if bestVertex >= 0 {
+			table.highlightCell(bestVertex, k+1)
+		}
+	}
+	return table, summary
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"strings"
+)
+
+// Sampler — генератор точек единичного гиперкуба [0,1)^dim.
+// Каждая точка задаёт по одному равномерному числу на ребро сети,
+// из которого методом обратной функции получается вес ребра.
+type Sampler interface {
+	Name() string
+	// Sample возвращает n точек размерности dim
+	Sample(n, dim int) [][]float64
+}
+
+var samplerNames = []string{"mc", "antithetic", "lhs", "sobol", "halton"}
+
+// Функция для выбора генератора по имени
+func newSampler(name string) (Sampler, error) {
+	switch name {
+	case "mc":
+		return monteCarloSampler{}, nil
+	case "antithetic":
+		return antitheticSampler{}, nil
+	case "lhs":
+		return latinHypercubeSampler{}, nil
+	case "sobol":
+		return sobolSampler{}, nil
+	case "halton":
+		return haltonSampler{}, nil
+	}
+	return nil, fmt.Errorf("неизвестный генератор %q, доступны: %s", name, strings.Join(samplerNames, ", "))
+}
+
+// Простой метод Монте-Карло: независимые равномерные числа
+type monteCarloSampler struct{}
+
+func (monteCarloSampler) Name() string { return "Монте-Карло" }
+
+func (monteCarloSampler) Sample(n, dim int) [][]float64 {
+	samples := make([][]float64, n)
+	for i := range samples {
+		samples[i] = make([]float64, dim)
+		for d := range samples[i] {
+			samples[i][d] = rand.Float64()
+		}
+	}
+	return samples
+}
+
+// Антитетические переменные: к каждой точке u добавляется точка 1-u
+type antitheticSampler struct{}
+
+func (antitheticSampler) Name() string { return "Антитетические переменные" }
+
+func (antitheticSampler) Sample(n, dim int) [][]float64 {
+	samples := make([][]float64, 0, n+1)
+	for len(samples) < n {
+		u := make([]float64, dim)
+		v := make([]float64, dim)
+		for d := range u {
+			u[d] = rand.Float64()
+			v[d] = 1 - u[d]
+		}
+		samples = append(samples, u, v)
+	}
+	return samples[:n]
+}
+
+// Латинский гиперкуб: по каждой координате ровно одна точка в каждом из n слоёв
+type latinHypercubeSampler struct{}
+
+func (latinHypercubeSampler) Name() string { return "Латинский гиперкуб" }
+
+func (latinHypercubeSampler) Sample(n, dim int) [][]float64 {
+	samples := make([][]float64, n)
+	for i := range samples {
+		samples[i] = make([]float64, dim)
+	}
+	for d := 0; d < dim; d++ {
+		perm := rand.Perm(n)
+		for i := range samples {
+			samples[i][d] = (float64(perm[i]) + rand.Float64()) / float64(n)
+		}
+	}
+	return samples
+}
+
+// Параметры направляющих чисел Соболя (Joe, Kuo, new-joe-kuo-6.21201)
+// для размерностей 2..21: степень многочлена s, коэффициенты a и начальные m_i
+var sobolParams = []struct {
+	s int
+	a uint32
+	m []uint32
+}{
+	{1, 0, []uint32{1}},
+	{2, 1, []uint32{1, 3}},
+	{3, 1, []uint32{1, 3, 1}},
+	{3, 2, []uint32{1, 1, 1}},
+	{4, 1, []uint32{1, 1, 3, 3}},
+	{4, 4, []uint32{1, 3, 5, 13}},
+	{5, 2, []uint32{1, 1, 5, 5, 17}},
+	{5, 4, []uint32{1, 1, 5, 5, 5}},
+	{5, 7, []uint32{1, 1, 7, 11, 19}},
+	{5, 11, []uint32{1, 1, 5, 1, 1}},
+	{5, 13, []uint32{1, 1, 1, 3, 11}},
+	{5, 14, []uint32{1, 3, 5, 5, 31}},
+	{6, 1, []uint32{1, 3, 3, 9, 7, 49}},
+	{6, 13, []uint32{1, 1, 1, 15, 21, 21}},
+	{6, 16, []uint32{1, 3, 1, 13, 27, 49}},
+	{6, 19, []uint32{1, 1, 1, 15, 7, 5}},
+	{6, 22, []uint32{1, 3, 1, 15, 13, 25}},
+	{6, 25, []uint32{1, 1, 5, 5, 19, 61}},
+	{7, 1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{7, 4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+}
+
+const sobolBits = 32
+
+// Последовательность Соболя со случайным цифровым сдвигом,
+// чтобы независимые повторы давали несмещённую оценку дисперсии
+type sobolSampler struct{}
+
+func (sobolSampler) Name() string { return "Соболь" }
+
+func (sobolSampler) Sample(n, dim int) [][]float64 {
+	shift := make([]uint32, min(dim, len(sobolParams)+1))
+	for d := range shift {
+		shift[d] = rand.Uint32()
+	}
+	samples := sobolPoints(n, dim, shift)
+
+	// Размерности сверх таблицы sobolParams заполняются последовательностью Хэлтона
+	if dim > len(shift) {
+		tail := haltonSampler{}.Sample(n, dim-len(shift))
+		for i := range samples {
+			copy(samples[i][len(shift):], tail[i])
+		}
+	}
+	return samples
+}
+
+// Функция для построения n точек Соболя в первых len(shift) из dim
+// размерностей с цифровым сдвигом shift (нулевой сдвиг — исходная
+// последовательность, начиная с нулевой точки)
+func sobolPoints(n, dim int, shift []uint32) [][]float64 {
+	directions := sobolDirections(len(shift))
+	samples := make([][]float64, n)
+	x := make([]uint32, len(shift))
+	for i := range samples {
+		if i > 0 {
+			// Код Грея: меняется одно направляющее число — по младшему нулевому биту i-1
+			c := bits.TrailingZeros32(^uint32(i - 1))
+			for d := range x {
+				x[d] ^= directions[d][c]
+			}
+		}
+		samples[i] = make([]float64, dim)
+		for d := range x {
+			samples[i][d] = float64(x[d]^shift[d]) / (1 << sobolBits)
+		}
+	}
+	return samples
+}
+
+// Функция для вычисления направляющих чисел Соболя
+func sobolDirections(dim int) [][]uint32 {
+	directions := make([][]uint32, dim)
+	for d := range directions {
+		v := make([]uint32, sobolBits)
+		if d == 0 {
+			for i := range v {
+				v[i] = 1 << (sobolBits - 1 - i)
+			}
+			directions[d] = v
+			continue
+		}
+		p := sobolParams[d-1]
+		for i := 0; i < p.s; i++ {
+			v[i] = p.m[i] << (sobolBits - 1 - i)
+		}
+		for i := p.s; i < sobolBits; i++ {
+			v[i] = v[i-p.s] ^ (v[i-p.s] >> p.s)
+			for k := 1; k < p.s; k++ {
+				v[i] ^= ((p.a >> (p.s - 1 - k)) & 1) * v[i-k]
+			}
+		}
+		directions[d] = v
+	}
+	return directions
+}
+
+// Последовательность Хэлтона со случайным сдвигом Кранли–Паттерсона
+type haltonSampler struct{}
+
+func (haltonSampler) Name() string { return "Хэлтон" }
+
+func (haltonSampler) Sample(n, dim int) [][]float64 {
+	shift := make([]float64, dim)
+	for d := range shift {
+		shift[d] = rand.Float64()
+	}
+	return haltonPoints(n, shift)
+}
+
+// Функция для построения n точек Хэлтона размерности len(shift) со сдвигом
+// по модулю 1 (нулевой сдвиг — исходная последовательность с первой точки)
+func haltonPoints(n int, shift []float64) [][]float64 {
+	bases := firstPrimes(len(shift))
+	samples := make([][]float64, n)
+	for i := range samples {
+		samples[i] = make([]float64, len(shift))
+		for d, base := range bases {
+			u := radicalInverse(i+1, base) + shift[d]
+			samples[i][d] = u - math.Floor(u)
+		}
+	}
+	return samples
+}
+
+// Функция для вычисления обратного по основанию base числа i
+func radicalInverse(i, base int) float64 {
+	result := 0.0
+	f := 1.0 / float64(base)
+	for i > 0 {
+		result += f * float64(i%base)
+		i /= base
+		f /= float64(base)
+	}
+	return result
+}
+
+// Функция для получения первых n простых чисел
+func firstPrimes(n int) []int {
+	primes := make([]int, 0, n)
+	for candidate := 2; len(primes) < n; candidate++ {
+		isPrime := true
+		for _, p := range primes {
+			if p*p > candidate {
+				break
+			}
+			if candidate%p == 0 {
+				isPrime = false
+				break
+			}
+		}
+		if isPrime {
+			primes = append(primes, candidate)
+		}
+	}
+	return primes
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSobolPoints(t *testing.T) {
+	// Первые 8 точек последовательности Соболя с направляющими числами
+	// Joe–Kuo (new-joe-kuo-6.21201) без скремблирования, по размерностям
+	want := [][]float64{
+		{0, 0.5, 0.75, 0.25, 0.375, 0.875, 0.625, 0.125},
+		{0, 0.5, 0.25, 0.75, 0.375, 0.875, 0.125, 0.625},
+		{0, 0.5, 0.25, 0.75, 0.625, 0.125, 0.875, 0.375},
+	}
+	points := sobolPoints(8, len(want), make([]uint32, len(want)))
+	for d := range want {
+		for i, expected := range want[d] {
+			if points[i][d] != expected {
+				t.Errorf("размерность %d, точка %d: %v, ожидалось %v", d+1, i, points[i][d], expected)
+			}
+		}
+	}
+}
+
+func TestHaltonPoints(t *testing.T) {
+	// Обратные по основаниям 2, 3 и 5 числа 1..5
+	want := [][]float64{
+		{1.0 / 2, 1.0 / 4, 3.0 / 4, 1.0 / 8, 5.0 / 8},
+		{1.0 / 3, 2.0 / 3, 1.0 / 9, 4.0 / 9, 7.0 / 9},
+		{1.0 / 5, 2.0 / 5, 3.0 / 5, 4.0 / 5, 1.0 / 25},
+	}
+	points := haltonPoints(5, make([]float64, len(want)))
+	for d := range want {
+		for i, expected := range want[d] {
+			if math.Abs(points[i][d]-expected) > 1e-12 {
+				t.Errorf("основание %d, точка %d: %v, ожидалось %v", firstPrimes(3)[d], i+1, points[i][d], expected)
+			}
+		}
+	}
+}
+
+func TestLatinHypercubeStrata(t *testing.T) {
+	tests := []struct{ n, dim int }{{1, 3}, {10, 4}, {97, 2}}
+	for _, tt := range tests {
+		samples := latinHypercubeSampler{}.Sample(tt.n, tt.dim)
+		for d := 0; d < tt.dim; d++ {
+			counts := make([]int, tt.n)
+			for _, sample := range samples {
+				counts[int(sample[d]*float64(tt.n))]++
+			}
+			for stratum, count := range counts {
+				if count != 1 {
+					t.Errorf("n = %d, размерность %d: в слое %d точек %d", tt.n, d, stratum, count)
+				}
+			}
+		}
+	}
+}
+
+func TestAntitheticPairs(t *testing.T) {
+	tests := []struct{ n, dim int }{{2, 1}, {10, 5}, {7, 3}}
+	for _, tt := range tests {
+		samples := antitheticSampler{}.Sample(tt.n, tt.dim)
+		if len(samples) != tt.n {
+			t.Fatalf("n = %d: получено %d точек", tt.n, len(samples))
+		}
+		for i := 0; i+1 < len(samples); i += 2 {
+			for d := range samples[i] {
+				if sum := samples[i][d] + samples[i+1][d]; math.Abs(sum-1) > 1e-12 {
+					t.Errorf("n = %d, пара %d, размерность %d: u + u' = %v", tt.n, i/2, d, sum)
+				}
+			}
+		}
+	}
+}
+
+func TestSamplersInUnitCube(t *testing.T) {
+	for _, name := range samplerNames {
+		sampler, err := newSampler(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Размерность больше таблицы Соболя проверяет и дополнение Хэлтоном
+		for _, sample := range sampler.Sample(64, len(sobolParams)+3) {
+			for _, u := range sample {
+				if u < 0 || u >= 1 {
+					t.Fatalf("%s: точка %v вне [0, 1)", name, u)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Результат изменения времени проезда по одному ребру
+type edgeSensitivity struct {
+	edge                    int     // индекс ребра в распределении
+	low, high               float64 // уменьшенное и увеличенное время проезда
+	lowOptimal, highOptimal int     // оптимальная вершина после изменения
+	lowValue, highValue     float64 // значение критерия в оптимальной вершине
+}
+
+// Детерминированный анализ чувствительности: все рёбра, кроме одного,
+// имеют среднее время проезда
+type sensitivityAnalysis struct {
+	weights []float64 // средние времена проезда
+	optimal int       // оптимальная вершина сети средних времён
+	value   float64
+	edges   []edgeSensitivity
+}
+
+// Функция для вычисления границ изменения времени проезда на ±percent % от среднего
+func percentBounds(distribution []edgeDistribution, percent float64) [][2]float64 {
+	bounds := make([][2]float64, len(distribution))
+	for k, d := range distribution {
+		bounds[k] = [2]float64{math.Max(d.mean*(1-percent/100), 0), d.mean * (1 + percent/100)}
+	}
+	return bounds
+}
+
+// Функция для получения наблюдавшихся минимума и максимума времени проезда
+// по строкам исходных данных "ребро,замер1,замер2,..."
+func observedBounds(data [][]string) ([][2]float64, error) {
+	bounds := make([][2]float64, len(data))
+	for k, record := range data {
+		if len(record) < 2 {
+			return nil, fmt.Errorf("ребро %s: нет замеров", record[0])
+		}
+		bounds[k] = [2]float64{math.Inf(1), math.Inf(-1)}
+		for _, field := range record[1:] {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("ребро %s: некорректный замер %q", record[0], field)
+			}
+			bounds[k][0] = math.Min(bounds[k][0], value)
+			bounds[k][1] = math.Max(bounds[k][1], value)
+		}
+	}
+	return bounds, nil
+}
+
+// Функция для нахождения оптимальной вершины сети и значения критерия в ней
+func optimalVertex(network *Graph) (int, float64) {
+	distMatrix := shortestDistances(network)
+	extRad, intRad := calculateExternalDistances(distMatrix), calculateInternalDistances(distMatrix)
+	optimal := optimalVertexIndex(intRad, extRad)
+	if optimal < 0 {
+		return -1, math.Inf(1)
+	}
+	return optimal, eccentricity.value(extRad[optimal], intRad[optimal])
+}
+
+// Функция для анализа чувствительности: время проезда по каждому ребру по
+// очереди заменяется нижней и верхней границей, остальные рёбра — средние
+func analyzeSensitivity(points []string, distribution []edgeDistribution, bounds [][2]float64) sensitivityAnalysis {
+	analysis := sensitivityAnalysis{weights: make([]float64, len(distribution))}
+	for k, d := range distribution {
+		analysis.weights[k] = d.mean
+	}
+	analysis.optimal, analysis.value = optimalVertex(networkFromWeights(points, distribution, analysis.weights))
+
+	weights := slices.Clone(analysis.weights)
+	for k := range distribution {
+		result := edgeSensitivity{edge: k, low: bounds[k][0], high: bounds[k][1]}
+		weights[k] = result.low
+		result.lowOptimal, result.lowValue = optimalVertex(networkFromWeights(points, distribution, weights))
+		weights[k] = result.high
+		result.highOptimal, result.highValue = optimalVertex(networkFromWeights(points, distribution, weights))
+		weights[k] = analysis.weights[k]
+		analysis.edges = append(analysis.edges, result)
+	}
+	return analysis
+}
+
+// Функция для вычисления размаха изменения критерия; бесконечные
+// значения (сеть стала несвязной) считаются нулевым изменением
+func (a sensitivityAnalysis) swing(result edgeSensitivity) (low, high float64) {
+	if !math.IsInf(result.lowValue, 1) && !math.IsInf(a.value, 1) {
+		low = result.lowValue - a.value
+	}
+	if !math.IsInf(result.highValue, 1) && !math.IsInf(a.value, 1) {
+		high = result.highValue - a.value
+	}
+	return low, high
+}
+
+// Функция для построения таблицы изменений рёбер, меняющих оптимальную вершину
+func sensitivityFlipTable(points []string, distribution []edgeDistribution, analysis sensitivityAnalysis) [][]string {
+	table := [][]string{{"Ребро", "Среднее время", "Изменённое время", "Оптимальная вершина", "Значение критерия", "Изменение критерия"}}
+	vertex := func(i int) string {
+		if i < 0 {
+			return "не определена"
+		}
+		return points[i]
+	}
+	for _, result := range analysis.edges {
+		d := distribution[result.edge]
+		lowSwing, highSwing := analysis.swing(result)
+		for _, change := range []struct {
+			weight  float64
+			optimal int
+			value   float64
+			swing   float64
+		}{
+			{result.low, result.lowOptimal, result.lowValue, lowSwing},
+			{result.high, result.highOptimal, result.highValue, highSwing},
+		} {
+			if change.optimal == analysis.optimal {
+				continue
+			}
+			table = append(table, []string{
+				d.origin + ":" + d.destination,
+				fmt.Sprintf("%.2f", analysis.weights[result.edge]),
+				fmt.Sprintf("%.2f", change.weight),
+				fmt.Sprintf("%s → %s", vertex(analysis.optimal), vertex(change.optimal)),
+				formatDistance(change.value),
+				fmt.Sprintf("%+.2f", change.swing),
+			})
+		}
+	}
+	if len(table) == 1 {
+		table = append(table, []string{"—", "—", "—", fmt.Sprintf("%s (не меняется)", vertex(analysis.optimal)), "—", "—"})
+	}
+	return table
+}
+
+// Функция для построения торнадо-диаграммы: рёбра упорядочены по размаху
+// изменения критерия в оптимальной вершине, наибольший — сверху
+func createTornadoChart(distribution []edgeDistribution, analysis sensitivityAnalysis, title, filename string) {
+	results := slices.Clone(analysis.edges)
+	sort.SliceStable(results, func(i, j int) bool {
+		lowI, highI := analysis.swing(results[i])
+		lowJ, highJ := analysis.swing(results[j])
+		return highI-lowI < highJ-lowJ
+	})
+
+	labels := make([]string, len(results))
+	lows := make(plotter.Values, len(results))
+	highs := make(plotter.Values, len(results))
+	for i, result := range results {
+		d := distribution[result.edge]
+		labels[i] = d.origin + ":" + d.destination
+		lows[i], highs[i] = analysis.swing(result)
+	}
+
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "Изменение: " + strings.ToLower(eccentricity.title())
+	p.Y.Label.Text = "Ребро"
+
+	if len(results) > 0 {
+		lowBars, err := plotter.NewBarChart(lows, vg.Points(12))
+		if err != nil {
+			log.Fatalf("Unable to create tornado chart: %v", err)
+		}
+		highBars, err := plotter.NewBarChart(highs, vg.Points(12))
+		if err != nil {
+			log.Fatalf("Unable to create tornado chart: %v", err)
+		}
+		lowBars.Horizontal, highBars.Horizontal = true, true
+		lowBars.Color = color.RGBA{R: 70, G: 130, B: 180, A: 255}
+		highBars.Color = color.RGBA{R: 205, G: 92, B: 92, A: 255}
+		p.Add(lowBars, highBars, plotter.NewGrid())
+		p.Legend.Add("нижняя граница", lowBars)
+		p.Legend.Add("верхняя граница", highBars)
+		p.Legend.Top = true
+		p.NominalY(labels...)
+		// Запас справа под легенду
+		p.X.Max += 0.35 * (p.X.Max - p.X.Min)
+	}
+
+	height := vg.Length(max(len(results), 8)) * vg.Points(18)
+	if err := savePlot(p, 8*vg.Inch, height, filename); err != nil {
+		log.Fatalf("Unable to save tornado chart: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Алгоритмы поиска кратчайших путей между всеми парами вершин
+const (
+	backendAuto    = "auto"    // выбор по размеру и плотности графа
+	backendDense   = "dense"   // Дейкстра с линейным поиском минимума, O(V^2) на вершину
+	backendHeap    = "heap"    // Дейкстра на двоичной куче, O(E log V) на вершину
+	backendFloyd   = "floyd"   // Флойд–Уоршелл, O(V^3)
+	backendJohnson = "johnson" // Джонсон: Беллман–Форд и Дейкстра на куче, допускает отрицательные веса
+)
+
+var shortestPathBackends = []string{backendAuto, backendDense, backendHeap, backendFloyd, backendJohnson}
+
+// Пороги выбора алгоритма по замерам go test -bench APSP на случайных сетях.
+// До denseMaxVertices вершин выигрыш других алгоритмов не превышает 20–30%
+// и зависит от машины, поэтому остаётся исходная Дейкстра с массивом.
+// Флойд–Уоршелл быстрее кучи, пока граф не слишком велик и не слишком разрежен.
+const (
+	denseMaxVertices = 20
+	floydMaxVertices = 200
+	floydMinDensity  = 0.1
+)
+
+// Функция для вычисления матрицы кратчайших расстояний выбранным алгоритмом
+func allPairsShortestPaths(graph *Graph, backend string) (DistanceMatrix, error) {
+	if backend == backendAuto {
+		backend = selectShortestPathBackend(graph)
+	}
+	switch backend {
+	case backendDense:
+		return dijkstraAll(graph), nil
+	case backendHeap:
+		return heapDijkstraAll(graph), nil
+	case backendFloyd:
+		return floydWarshall(graph), nil
+	case backendJohnson:
+		return johnson(graph)
+	}
+	return DistanceMatrix{}, fmt.Errorf("неизвестный алгоритм кратчайших путей %q", backend)
+}
+
+// Функция для автоматического выбора алгоритма по структуре графа
+func selectShortestPathBackend(graph *Graph) string {
+	n := graph.Len()
+	arcs := 0
+	for i := 0; i < n; i++ {
+		for _, arc := range graph.Arcs(i) {
+			if arc.Weight < 0 {
+				return backendJohnson
+			}
+			arcs++
+		}
+	}
+	if n <= denseMaxVertices {
+		return backendDense
+	}
+	density := float64(arcs) / float64(n*(n-1))
+	if n <= floydMaxVertices && density >= floydMinDensity {
+		return backendFloyd
+	}
+	return backendHeap
+}
+
+// Элемент очереди с приоритетом для алгоритма Дейкстры
+type queueItem struct {
+	vertex int
+	dist   float64
+}
+
+// Двоичная куча по расстоянию. Своя реализация вместо container/heap,
+// чтобы не упаковывать элементы в interface{} на каждой операции.
+type distanceQueue []queueItem
+
+func (q *distanceQueue) push(item queueItem) {
+	*q = append(*q, item)
+	h := *q
+	for i := len(h) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if h[parent].dist <= h[i].dist {
+			break
+		}
+		h[parent], h[i] = h[i], h[parent]
+		i = parent
+	}
+}
+
+func (q *distanceQueue) pop() queueItem {
+	h := *q
+	top := h[0]
+	last := len(h) - 1
+	h[0] = h[last]
+	h = h[:last]
+	for i := 0; ; {
+		smallest := i
+		if l := 2*i + 1; l < len(h) && h[l].dist < h[smallest].dist {
+			smallest = l
+		}
+		if r := 2*i + 2; r < len(h) && h[r].dist < h[smallest].dist {
+			smallest = r
+		}
+		if smallest == i {
+			break
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+	*q = h
+	return top
+}
+
+func heapDijkstraAll(graph *Graph) DistanceMatrix {
+	distanceMatrix := newDistanceMatrix(graph.Vertices)
+	queue := make(distanceQueue, 0, graph.Len())
+	for i := range distanceMatrix.Dist {
+		distanceMatrix.Dist[i], distanceMatrix.Pred[i] = heapDijkstra(graph, i, &queue)
+	}
+	return distanceMatrix
+}
+
+// Дейкстра на двоичной куче с ленивым удалением устаревших элементов.
+// Очередь передаётся снаружи, чтобы переиспользовать память между запусками.
+func heapDijkstra(graph *Graph, start int, queue *distanceQueue) ([]float64, []int) {
+	distances := make([]float64, graph.Len())
+	predecessors := make([]int, graph.Len())
+	for i := range distances {
+		distances[i] = math.Inf(1)
+		predecessors[i] = -1
+	}
+	distances[start] = 0
+
+	*queue = (*queue)[:0]
+	queue.push(queueItem{vertex: start, dist: 0})
+	for len(*queue) > 0 {
+		item := queue.pop()
+		if item.dist > distances[item.vertex] {
+			continue
+		}
+		for _, arc := range graph.Arcs(item.vertex) {
+			newDist := item.dist + arc.Weight
+			if newDist < distances[arc.To] {
+				distances[arc.To] = newDist
+				predecessors[arc.To] = item.vertex
+				queue.push(queueItem{vertex: arc.To, dist: newDist})
+			}
+		}
+	}
+	return distances, predecessors
+}
+
+func floydWarshall(graph *Graph) DistanceMatrix {
+	n := graph.Len()
+	distanceMatrix := newDistanceMatrix(graph.Vertices)
+	dist, pred := distanceMatrix.Dist, distanceMatrix.Pred
+	for i := 0; i < n; i++ {
+		for j := range dist[i] {
+			dist[i][j] = math.Inf(1)
+			pred[i][j] = -1
+		}
+		dist[i][i] = 0
+		for _, arc := range graph.Arcs(i) {
+			if arc.To != i && arc.Weight < dist[i][arc.To] {
+				dist[i][arc.To] = arc.Weight
+				pred[i][arc.To] = i
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if math.IsInf(dist[i][k], 1) {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if d := dist[i][k] + dist[k][j]; d < dist[i][j] {
+					dist[i][j] = d
+					pred[i][j] = pred[k][j]
+				}
+			}
+		}
+	}
+	return distanceMatrix
+}
+
+// Алгоритм Джонсона: потенциалы Беллмана–Форда делают веса неотрицательными,
+// после чего из каждой вершины запускается Дейкстра на куче
+func johnson(graph *Graph) (DistanceMatrix, error) {
+	n := graph.Len()
+
+	// Беллман–Форд из фиктивной вершины, соединённой со всеми нулевыми дугами
+	potential := make([]float64, n)
+	for iteration := 0; iteration < n; iteration++ {
+		changed := false
+		for u := 0; u < n; u++ {
+			for _, arc := range graph.Arcs(u) {
+				if d := potential[u] + arc.Weight; d < potential[arc.To] {
+					potential[arc.To] = d
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+		if iteration == n-1 {
+			return DistanceMatrix{}, fmt.Errorf("граф содержит цикл отрицательного веса")
+		}
+	}
+
+	reweighted := NewGraph(graph.Vertices)
+	for u := 0; u < n; u++ {
+		for _, arc := range graph.Arcs(u) {
+			reweighted.SetEdge(u, arc.To, arc.Weight+potential[u]-potential[arc.To])
+		}
+	}
+
+	distanceMatrix := heapDijkstraAll(reweighted)
+	for u, row := range distanceMatrix.Dist {
+		for v := range row {
+			if !math.IsInf(row[v], 1) {
+				row[v] += potential[v] - potential[u]
+			}
+		}
+	}
+	return distanceMatrix, nil
+}
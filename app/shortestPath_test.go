@@ -0,0 +1,174 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// Функция для генерации случайной дорожной сети-решётки: каждая вершина
+// соединена с соседями справа и снизу, а также с небольшой долей
+// диагональных соседей, веса — от 1 до 20 минут
+func generateGridNetwork(rng *rand.Rand, n int) *Graph {
+	vertices := make([]string, n)
+	for i := range vertices {
+		vertices[i] = strconv.Itoa(i + 1)
+	}
+	graph := NewGraph(vertices)
+
+	width := int(math.Ceil(math.Sqrt(float64(n))))
+	for i := 0; i < n; i++ {
+		if (i+1)%width != 0 && i+1 < n {
+			graph.SetUndirectedEdge(i, i+1, 1+19*rng.Float64())
+		}
+		if i+width < n {
+			graph.SetUndirectedEdge(i, i+width, 1+19*rng.Float64())
+		}
+		if (i+1)%width != 0 && i+width+1 < n && rng.Float64() < 0.2 {
+			graph.SetUndirectedEdge(i, i+width+1, 1+19*rng.Float64())
+		}
+	}
+	return graph
+}
+
+// Функция для генерации случайного ориентированного графа: дуга между парой
+// вершин есть с вероятностью density, часть весов нулевая. Последние
+// isolated вершин не связаны с остальными, расстояния до них — +Inf.
+func generateDirectedNetwork(rng *rand.Rand, n, isolated int, density float64) *Graph {
+	vertices := make([]string, n)
+	for i := range vertices {
+		vertices[i] = strconv.Itoa(i + 1)
+	}
+	graph := NewGraph(vertices)
+	connected := n - isolated
+	for i := 0; i < connected; i++ {
+		for j := 0; j < connected; j++ {
+			if i == j || rng.Float64() >= density {
+				continue
+			}
+			weight := float64(rng.Intn(20))
+			if rng.Float64() < 0.2 {
+				weight = 0
+			}
+			graph.SetEdge(i, j, weight)
+		}
+	}
+	return graph
+}
+
+func TestShortestPathBackendsMatchDijkstra(t *testing.T) {
+	tests := []struct {
+		name        string
+		n, isolated int
+		density     float64
+	}{
+		{"разреженный", 30, 0, 0.08},
+		{"разреженный с недостижимыми", 30, 3, 0.08},
+		{"плотный", 20, 0, 0.7},
+		{"плотный с недостижимыми", 20, 2, 0.7},
+		{"без дуг", 5, 0, 0},
+	}
+	backends := []string{backendAuto, backendHeap, backendFloyd, backendJohnson}
+	for _, tt := range tests {
+		rng := rand.New(rand.NewSource(int64(tt.n*100 + tt.isolated)))
+		for round := 0; round < 5; round++ {
+			graph := generateDirectedNetwork(rng, tt.n, tt.isolated, tt.density)
+			want := dijkstraAll(graph)
+			for _, backend := range backends {
+				got, err := allPairsShortestPaths(graph, backend)
+				if err != nil {
+					t.Fatalf("%s, %s: %v", tt.name, backend, err)
+				}
+				for i := range want.Dist {
+					for j := range want.Dist[i] {
+						if got.Dist[i][j] != want.Dist[i][j] {
+							t.Fatalf("%s, %s: расстояние %d→%d = %v, ожидалось %v", tt.name, backend, i, j, got.Dist[i][j], want.Dist[i][j])
+						}
+						// Кратчайших путей может быть несколько, поэтому
+						// проверяется длина восстановленного пути, а не он сам
+						if length, ok := pathLength(graph, got.Path(i, j)); ok != !math.IsInf(want.Dist[i][j], 1) || ok && length != want.Dist[i][j] {
+							t.Fatalf("%s, %s: путь %d→%d %v длиной %v, ожидалось %v", tt.name, backend, i, j, got.Path(i, j), length, want.Dist[i][j])
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Функция для вычисления длины пути по дугам графа; false — пути нет
+func pathLength(graph *Graph, path []int) (float64, bool) {
+	if path == nil {
+		return 0, false
+	}
+	length := 0.0
+	for k := 1; k < len(path); k++ {
+		weight, ok := graph.Edge(path[k-1], path[k])
+		if !ok {
+			return 0, false
+		}
+		length += weight
+	}
+	return length, true
+}
+
+func TestJohnsonNegativeWeights(t *testing.T) {
+	graph := NewGraph([]string{"1", "2", "3", "4"})
+	graph.SetEdge(0, 1, 4)
+	graph.SetEdge(1, 2, -2)
+	graph.SetEdge(0, 2, 3)
+	graph.SetEdge(2, 3, 1)
+	got, err := johnson(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := floydWarshall(graph)
+	for i := range want.Dist {
+		for j := range want.Dist[i] {
+			if got.Dist[i][j] != want.Dist[i][j] {
+				t.Errorf("расстояние %d→%d = %v, ожидалось %v", i, j, got.Dist[i][j], want.Dist[i][j])
+			}
+		}
+	}
+	if selectShortestPathBackend(graph) != backendJohnson {
+		t.Errorf("для отрицательных весов выбран %s", selectShortestPathBackend(graph))
+	}
+
+	// Цикл 2 → 3 → 2 отрицательного веса
+	graph.SetEdge(2, 1, 1)
+	if _, err := johnson(graph); err == nil {
+		t.Error("ожидалась ошибка для цикла отрицательного веса")
+	}
+}
+
+// Функция для замера одного расчёта матрицы расстояний на сети-решётке из n вершин
+func benchmarkAPSP(b *testing.B, backend string, n int) {
+	graph := generateGridNetwork(rand.New(rand.NewSource(1)), n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := allPairsShortestPaths(graph, backend); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAPSPDense_10(b *testing.B)   { benchmarkAPSP(b, backendDense, 10) }
+func BenchmarkAPSPDense_100(b *testing.B)  { benchmarkAPSP(b, backendDense, 100) }
+func BenchmarkAPSPDense_1000(b *testing.B) { benchmarkAPSP(b, backendDense, 1000) }
+
+func BenchmarkAPSPHeap_10(b *testing.B)   { benchmarkAPSP(b, backendHeap, 10) }
+func BenchmarkAPSPHeap_100(b *testing.B)  { benchmarkAPSP(b, backendHeap, 100) }
+func BenchmarkAPSPHeap_1000(b *testing.B) { benchmarkAPSP(b, backendHeap, 1000) }
+
+func BenchmarkAPSPFloyd_10(b *testing.B)   { benchmarkAPSP(b, backendFloyd, 10) }
+func BenchmarkAPSPFloyd_100(b *testing.B)  { benchmarkAPSP(b, backendFloyd, 100) }
+func BenchmarkAPSPFloyd_1000(b *testing.B) { benchmarkAPSP(b, backendFloyd, 1000) }
+
+func BenchmarkAPSPJohnson_10(b *testing.B)   { benchmarkAPSP(b, backendJohnson, 10) }
+func BenchmarkAPSPJohnson_100(b *testing.B)  { benchmarkAPSP(b, backendJohnson, 100) }
+func BenchmarkAPSPJohnson_1000(b *testing.B) { benchmarkAPSP(b, backendJohnson, 1000) }
+
+func BenchmarkAPSPAuto_10(b *testing.B)   { benchmarkAPSP(b, backendAuto, 10) }
+func BenchmarkAPSPAuto_100(b *testing.B)  { benchmarkAPSP(b, backendAuto, 100) }
+func BenchmarkAPSPAuto_1000(b *testing.B) { benchmarkAPSP(b, backendAuto, 1000) }
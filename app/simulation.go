@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Параметры моделирования размещения
+type simulationOptions struct {
+	criteria   []placementCriterion // критерии, решаемые на каждой сети
+	coverTime  float64              // норматив времени доезда, 0 — покрытие не оценивается
+	facilities int                  // число пунктов для оценки покрытия
+	demand     []float64
+	failure    float64 // вероятность перекрытия каждого ребра в сети
+}
+
+// Результаты моделирования размещения
+type placementSimulation struct {
+	wins         map[string]int // сколько раз каждая вершина оказалась оптимальной
+	iterations   int
+	failure      float64
+	disconnected int // сети с недостижимыми вершинами, в которых оптимум не определён
+	// placements[критерий][набор пунктов] — сколько раз набор оказался оптимальным
+	placements map[string]map[string]int
+	coverage   coverageStats
+	// Внешний и внутренний радиусы вершин в каждой связной сети: [вершина][сеть]
+	externalRadii, internalRadii [][]float64
+	// Оптимальная вершина в каждой итерации по порядку, -1 — сеть несвязна
+	optimal []int
+}
+
+// Функция для моделирования размещения: iterations раз строится случайная
+// сеть с вершинами points по точкам генератора sampler и считается, сколько
+// раз каждая вершина (и каждый набор пунктов по критериям options.criteria)
+// оказались оптимальными
+func simulateOptimalVertices(points []string, distribution []edgeDistribution, sampler Sampler, iterations int, options simulationOptions) placementSimulation {
+	simulation := placementSimulation{
+		wins:          make(map[string]int, len(points)),
+		iterations:    iterations,
+		failure:       options.failure,
+		placements:    make(map[string]map[string]int, len(options.criteria)),
+		externalRadii: make([][]float64, len(points)),
+		internalRadii: make([][]float64, len(points)),
+		optimal:       make([]int, 0, iterations),
+	}
+	for _, val := range points {
+		simulation.wins[val] = 0
+	}
+	for _, criterion := range options.criteria {
+		simulation.placements[criterion.key] = make(map[string]int)
+	}
+	if options.coverTime > 0 {
+		simulation.coverage = coverageStats{
+			coverTime: options.coverTime,
+			full:      make(map[string]int),
+			share:     make(map[string]float64),
+		}
+	}
+
+	for _, u := range sampler.Sample(iterations, len(distribution)) {
+		weights := randomWeights(distribution, u)
+		if options.failure > 0 {
+			failEdges(weights, options.failure)
+		}
+		randomNetwork := networkFromWeights(points, distribution, weights)
+		distMatrix := shortestDistances(randomNetwork)
+		for _, criterion := range options.criteria {
+			solution := criterion.solve(distMatrix)
+			if !math.IsInf(solution.value, 1) {
+				simulation.placements[criterion.key][formatFacilities(points, solution.facilities)]++
+			}
+		}
+
+		if options.coverTime > 0 {
+			simulation.coverage.add(distMatrix, options.demand, options.facilities)
+		}
+
+		extRad, intRad := calculateExternalDistances(distMatrix), calculateInternalDistances(distMatrix)
+		optimal := optimalVertexIndex(intRad, extRad)
+		simulation.optimal = append(simulation.optimal, optimal)
+		if optimal < 0 {
+			simulation.disconnected++
+			continue
+		}
+		simulation.wins[points[optimal]]++
+		for i := range points {
+			simulation.externalRadii[i] = append(simulation.externalRadii[i], extRad[i])
+			simulation.internalRadii[i] = append(simulation.internalRadii[i], intRad[i])
+		}
+	}
+	return simulation
+}
+
+// Функция для сравнения генераторов: каждый генератор получает одинаковый
+// бюджет iterations, разбитый на replications независимых повторов.
+// Возвращает таблицу оценок вероятности оптимальности каждой вершины
+// и таблицу дисперсий этих оценок. Эффективность считается относительно
+// генератора mc; если он не сравнивается или его дисперсия нулевая,
+// вместо неё ставится прочерк.
+func compareSamplers(distribution []edgeDistribution, names []string, iterations, replications int) ([][]string, [][]string) {
+	perReplication := max(iterations/replications, 1)
+
+	estimates := [][]string{append([]string{"Генератор"}, peaks...)}
+	variances := [][]string{append(append([]string{"Генератор"}, peaks...), "Сумма дисперсий", "Эффективность относительно МК")}
+
+	baseline := math.NaN()
+	var totals []float64
+	for _, name := range names {
+		sampler, err := newSampler(name)
+		if err != nil {
+			continue
+		}
+
+		// Доли побед каждой вершины в каждом повторе
+		shares := make([][]float64, len(peaks))
+		for i := range shares {
+			shares[i] = make([]float64, replications)
+		}
+		for r := 0; r < replications; r++ {
+			simulation := simulateOptimalVertices(peaks, distribution, sampler, perReplication, simulationOptions{})
+			for i, peak := range peaks {
+				shares[i][r] = float64(simulation.wins[peak]) / float64(perReplication)
+			}
+		}
+
+		estimateRow := []string{sampler.Name()}
+		varianceRow := []string{sampler.Name()}
+		total := 0.0
+		for i := range peaks {
+			mean, variance := meanAndVariance(shares[i])
+			// Дисперсия итоговой оценки — среднего по повторам
+			variance /= float64(replications)
+			total += variance
+			estimateRow = append(estimateRow, fmt.Sprintf("%.4f", mean))
+			varianceRow = append(varianceRow, fmt.Sprintf("%.2e", variance))
+		}
+		if name == "mc" {
+			baseline = total
+		}
+		totals = append(totals, total)
+		estimates = append(estimates, estimateRow)
+		variances = append(variances, append(varianceRow, fmt.Sprintf("%.2e", total)))
+	}
+
+	for k, total := range totals {
+		efficiency := "—"
+		if baseline > 0 && total > 0 {
+			efficiency = fmt.Sprintf("%.2f", baseline/total)
+		}
+		variances[k+1] = append(variances[k+1], efficiency)
+	}
+	return estimates, variances
+}
+
+// Функция для вычисления выборочного среднего и несмещённой дисперсии
+func meanAndVariance(values []float64) (float64, float64) {
+	n := float64(len(values))
+	if n == 0 {
+		return 0, 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= n
+	if n < 2 {
+		return mean, 0
+	}
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / (n - 1)
+}
+
+// Функция для построения итоговой таблицы моделирования по выбранному критерию удалённости
+func simulationSummaryTable(simulation placementSimulation) [][]string {
+	best := ""
+	for _, peak := range peaks {
+		if best == "" || simulation.wins[peak] > simulation.wins[best] {
+			best = peak
+		}
+	}
+	table := [][]string{
+		{"Название", "Результат"},
+		{"Критерий оптимальности", eccentricity.title()},
+		{"Число итераций", fmt.Sprintf("%d", simulation.iterations)},
+		{"Вероятность перекрытия ребра", fmt.Sprintf("%g", simulation.failure)},
+		{"Сетей с недостижимыми вершинами", fmt.Sprintf("%d", simulation.disconnected)},
+	}
+	if best == "" || simulation.wins[best] == 0 {
+		return append(table, []string{"Наиболее часто оптимальная вершина", "—"})
+	}
+	return append(table,
+		[]string{"Наиболее часто оптимальная вершина", best},
+		[]string{"Доля итераций", fmt.Sprintf("%.4f", float64(simulation.wins[best])/float64(simulation.iterations))},
+	)
+}
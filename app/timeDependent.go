@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Шаг времени отправления при оценке размещения в течение дня, минуты
+const departureStep = 60
+
+// travelTimeFunction — время проезда по ребру в зависимости от момента
+// въезда на него: линейная интерполяция средних замеров по временным
+// срезам, до первого и после последнего среза — постоянная. Функция
+// удовлетворяет условию FIFO: выехавший позже не приедет раньше.
+type travelTimeFunction struct {
+	slots    []float64 // моменты срезов, минуты от полуночи, по возрастанию
+	duration []float64 // среднее время проезда в каждом срезе
+}
+
+// at возвращает время проезда при въезде на ребро в момент t
+func (f travelTimeFunction) at(t float64) float64 {
+	k, found := slices.BinarySearch(f.slots, t)
+	switch {
+	case found:
+		return f.duration[k]
+	case k == 0:
+		return f.duration[0]
+	case k == len(f.slots):
+		return f.duration[len(f.duration)-1]
+	}
+	share := (t - f.slots[k-1]) / (f.slots[k] - f.slots[k-1])
+	return f.duration[k-1] + share*(f.duration[k]-f.duration[k-1])
+}
+
+// Функция для разбора времени среза вида "09:00" в минуты от полуночи
+func parseSlot(value string) (float64, error) {
+	moment, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("некорректное время среза %q", value)
+	}
+	return float64(moment.Hour()*60 + moment.Minute()), nil
+}
+
+// Функция для записи момента в минутах от полуночи в виде "09:00"
+func formatSlot(minutes float64) string {
+	m := int(math.Round(minutes))
+	return fmt.Sprintf("%02d:%02d", m/60%24, m%60)
+}
+
+// Функция для построения функций времени проезда по рёбрам. header —
+// строка заголовка с временем среза каждого столбца замеров, data —
+// строки "ребро,замер1,замер2,..." в порядке рёбер распределения.
+func buildTravelTimeFunctions(header []string, data [][]string) ([]travelTimeFunction, error) {
+	if len(header) < 2 {
+		return nil, fmt.Errorf("нет строки с временем срезов")
+	}
+	columnSlots := make([]float64, len(header))
+	var slots []float64
+	for j := 1; j < len(header); j++ {
+		slot, err := parseSlot(header[j])
+		if err != nil {
+			return nil, err
+		}
+		columnSlots[j] = slot
+		if !slices.Contains(slots, slot) {
+			slots = append(slots, slot)
+		}
+	}
+	slices.Sort(slots)
+
+	functions := make([]travelTimeFunction, len(data))
+	for k, record := range data {
+		sums := make([]float64, len(slots))
+		counts := make([]int, len(slots))
+		for j := 1; j < len(record) && j < len(header); j++ {
+			value, err := strconv.ParseFloat(record[j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("ребро %s: некорректный замер %q", record[0], record[j])
+			}
+			s, _ := slices.BinarySearch(slots, columnSlots[j])
+			sums[s] += value
+			counts[s]++
+		}
+
+		f := travelTimeFunction{slots: slots, duration: make([]float64, len(slots))}
+		for s := range slots {
+			if counts[s] == 0 {
+				return nil, fmt.Errorf("ребро %s: нет замеров в %s", record[0], formatSlot(slots[s]))
+			}
+			f.duration[s] = sums[s] / float64(counts[s])
+			// FIFO: время проезда не может убывать быстрее, чем идут часы
+			if s > 0 {
+				f.duration[s] = math.Max(f.duration[s], f.duration[s-1]-(slots[s]-slots[s-1]))
+			}
+		}
+		functions[k] = f
+	}
+	return functions, nil
+}
+
+// Дуга сети с временем проезда, зависящим от момента въезда
+type timeDependentArc struct {
+	to     int
+	travel *travelTimeFunction
+}
+
+// timeDependentNetwork — дорожная сеть с временами проезда, зависящими
+// от времени отправления. Рёбра, как и в исходных данных, двусторонние.
+type timeDependentNetwork struct {
+	vertices []string
+	adj      [][]timeDependentArc
+}
+
+// Функция для построения сети по рёбрам распределения и их функциям времени проезда
+func newTimeDependentNetwork(points []string, distribution []edgeDistribution, functions []travelTimeFunction) *timeDependentNetwork {
+	network := &timeDependentNetwork{vertices: points, adj: make([][]timeDependentArc, len(points))}
+	for k, d := range distribution {
+		i, j := slices.Index(points, d.origin), slices.Index(points, d.destination)
+		if i < 0 || j < 0 || i == j {
+			continue
+		}
+		network.adj[i] = append(network.adj[i], timeDependentArc{to: j, travel: &functions[k]})
+		network.adj[j] = append(network.adj[j], timeDependentArc{to: i, travel: &functions[k]})
+	}
+	return network
+}
+
+// Функция для поиска самого раннего прибытия во все вершины при отправлении
+// из start в момент departure. Благодаря FIFO алгоритм Дейкстры остаётся
+// точным, если вес дуги вычислять в момент прибытия в её начало.
+// Возвращает время в пути (прибытие минус отправление) и предшественников.
+func (n *timeDependentNetwork) earliestArrival(start int, departure float64, queue *distanceQueue) ([]float64, []int) {
+	arrival := make([]float64, len(n.vertices))
+	predecessors := make([]int, len(n.vertices))
+	for i := range arrival {
+		arrival[i] = math.Inf(1)
+		predecessors[i] = -1
+	}
+	arrival[start] = departure
+
+	*queue = (*queue)[:0]
+	queue.push(queueItem{vertex: start, dist: departure})
+	for len(*queue) > 0 {
+		item := queue.pop()
+		if item.dist > arrival[item.vertex] {
+			continue
+		}
+		for _, arc := range n.adj[item.vertex] {
+			if t := item.dist + arc.travel.at(item.dist); t < arrival[arc.to] {
+				arrival[arc.to] = t
+				predecessors[arc.to] = item.vertex
+				queue.push(queueItem{vertex: arc.to, dist: t})
+			}
+		}
+	}
+	for i := range arrival {
+		arrival[i] -= departure
+	}
+	return arrival, predecessors
+}
+
+// Функция для вычисления матрицы времени в пути между всеми парами вершин
+// при отправлении в момент departure (минуты от полуночи)
+func (n *timeDependentNetwork) shortestPaths(departure float64) DistanceMatrix {
+	distanceMatrix := newDistanceMatrix(n.vertices)
+	queue := make(distanceQueue, 0, len(n.vertices))
+	for i := range distanceMatrix.Dist {
+		distanceMatrix.Dist[i], distanceMatrix.Pred[i] = n.earliestArrival(i, departure, &queue)
+	}
+	return distanceMatrix
+}
+
+// Функция для оценки размещения в течение дня: для каждого часа от первого
+// до последнего среза — значение критерия удалённости всех вершин.
+// Лучшая вершина в каждый момент выделяется. Вторая таблица — итоги по дню.
+func timeOfDayTables(network *timeDependentNetwork, functions []travelTimeFunction) (reportTable, [][]string) {
+	table := [][]string{append(append([]string{"Время отправления"}, network.vertices...), "Оптимальная вершина")}
+	summary := [][]string{{"Название", "Результат"}}
+	if len(functions) == 0 {
+		return tableFromRows(table), summary
+	}
+
+	slots := functions[0].slots
+	wins := make([]int, len(network.vertices))
+	var optimalByTime []int
+	worst := make([]float64, len(network.vertices))
+	for departure := slots[0]; departure <= slots[len(slots)-1]; departure += departureStep {
+		distMatrix := network.shortestPaths(departure)
+		extRad, intRad := calculateExternalDistances(distMatrix), calculateInternalDistances(distMatrix)
+		row := []string{formatSlot(departure)}
+		for i := range network.vertices {
+			value := eccentricity.value(extRad[i], intRad[i])
+			worst[i] = math.Max(worst[i], value)
+			row = append(row, formatDistance(value))
+		}
+		optimal := optimalVertexIndex(intRad, extRad)
+		optimalByTime = append(optimalByTime, optimal)
+		if optimal < 0 {
+			table = append(table, append(row, "не определена"))
+			continue
+		}
+		wins[optimal]++
+		table = append(table, append(row, network.vertices[optimal]))
+	}
+
+	frequent, minimax := 0, 0
+	for i := range network.vertices {
+		if wins[i] > wins[frequent] {
+			frequent = i
+		}
+		if worst[i] < worst[minimax] {
+			minimax = i
+		}
+	}
+	summary = append(summary,
+		[]string{"Критерий оптимальности", eccentricity.title()},
+		[]string{"Моментов отправления", fmt.Sprintf("%d", len(table)-1)},
+		[]string{"Чаще всего оптимальна", fmt.Sprintf("%s (%d из %d)", network.vertices[frequent], wins[frequent], len(table)-1)},
+		[]string{"Наименьшее наибольшее за день значение критерия", fmt.Sprintf("%s (%s)", network.vertices[minimax], formatDistance(worst[minimax]))},
+	)
+
+	result := tableFromRows(table)
+	for i, optimal := range optimalByTime {
+		if optimal >= 0 {
+			result.highlightCell(i, optimal+1)
+		}
+	}
+	return result, summary
+}
+
+// Функция для построения данных интерактивной диаграммы по строке таблицы
+// timeOfDayTables: значение критерия каждой вершины в момент отправления
+func timeOfDayChart(vertices []string, row []reportCell) reportChart {
+	chart := reportChart{XLabel: "Номер вершины", YLabel: eccentricity.title()}
+	for i, vertex := range vertices {
+		// недостижимые вершины («∞») отображаются столбцом нулевой высоты
+		value, _ := strconv.ParseFloat(row[i+1].Text, 64)
+		tooltip := fmt.Sprintf("%s: %s", vertex, row[i+1].Text)
+		if row[i+1].Highlight {
+			tooltip += " (оптимальна)"
+		}
+		chart.Labels = append(chart.Labels, vertex)
+		chart.Values = append(chart.Values, value)
+		chart.Tooltips = append(chart.Tooltips, tooltip)
+	}
+	return chart
+}
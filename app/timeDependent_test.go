@@ -0,0 +1,122 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTravelTimeFunctionAt(t *testing.T) {
+	// 09:00 — 10 мин, 12:00 — 40 мин
+	f := travelTimeFunction{slots: []float64{540, 720}, duration: []float64{10, 40}}
+	tests := []struct {
+		name   string
+		moment float64
+		want   float64
+	}{
+		{"до первого среза", 300, 10},
+		{"первый срез", 540, 10},
+		{"между срезами", 630, 25},
+		{"треть интервала", 600, 20},
+		{"последний срез", 720, 40},
+		{"после последнего среза", 1200, 40},
+	}
+	for _, tt := range tests {
+		if got := f.at(tt.moment); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: at(%s) = %v, ожидалось %v", tt.name, formatSlot(tt.moment), got, tt.want)
+		}
+	}
+}
+
+func TestBuildTravelTimeFunctionsFIFO(t *testing.T) {
+	header := []string{"", "09:00", "09:00", "09:30", "10:00"}
+	data := [][]string{
+		// Среднее в 09:00 — 60, в 09:30 замер 10: падение на 50 за 30 минут
+		// ограничивается до 60 − 30 = 30, в 10:00 рост не ограничивается
+		{"1:2", "50", "70", "10", "45"},
+		{"2:3", "5", "7", "8", "9"},
+	}
+	functions, err := buildTravelTimeFunctions(header, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]float64{{60, 30, 45}, {6, 8, 9}}
+	for k, f := range functions {
+		for s := range f.slots {
+			if f.duration[s] != want[k][s] {
+				t.Errorf("ребро %s, срез %s: %v, ожидалось %v", data[k][0], formatSlot(f.slots[s]), f.duration[s], want[k][s])
+			}
+		}
+		// Прибытие t + at(t) не убывает с моментом въезда
+		previous := math.Inf(-1)
+		for moment := 500.0; moment <= 640; moment += 0.5 {
+			arrival := moment + f.at(moment)
+			if arrival < previous-1e-9 {
+				t.Fatalf("ребро %s: въезд в %v даёт прибытие %v раньше предыдущего %v", data[k][0], moment, arrival, previous)
+			}
+			previous = arrival
+		}
+	}
+
+	if _, err := buildTravelTimeFunctions(header, [][]string{{"1:2", "5", "x", "5", "5"}}); err == nil {
+		t.Error("ожидалась ошибка для некорректного замера")
+	}
+}
+
+// Сеть из трёх вершин: A–B дорожает с 10 до 30 мин между 09:00 и 10:00,
+// B–C — всегда 20 мин, прямое ребро A–C — всегда 50 мин
+func hourlyTestNetwork() *timeDependentNetwork {
+	slots := []float64{540, 600}
+	functions := []travelTimeFunction{
+		{slots: slots, duration: []float64{10, 30}},
+		{slots: slots, duration: []float64{20, 20}},
+		{slots: slots, duration: []float64{50, 50}},
+	}
+	distribution := []edgeDistribution{
+		{edge: edge{origin: "A", destination: "B"}},
+		{edge: edge{origin: "B", destination: "C"}},
+		{edge: edge{origin: "A", destination: "C"}},
+	}
+	return newTimeDependentNetwork([]string{"A", "B", "C"}, distribution, functions)
+}
+
+func TestEarliestArrival(t *testing.T) {
+	network := hourlyTestNetwork()
+	tests := []struct {
+		departure float64
+		want      [3][3]float64
+	}{
+		// 09:00: A→B 10, A→C через B 10 + 20; C→A через B въезжает на A–B в 09:20: 20 + 10 + 20·20/60
+		{540, [3][3]float64{{0, 10, 30}, {10, 0, 20}, {20 + 10 + 20.0/3, 20, 0}}},
+		// 09:30 — между срезами: A→B = 10 + 0.5·20 = 20, A→C = 20 + 20 = 40;
+		// C→A через B въезжает на A–B в 09:50: 20 + 10 + 20·50/60
+		{570, [3][3]float64{{0, 20, 40}, {20, 0, 20}, {20 + 10 + 20*50.0/60, 20, 0}}},
+		// 10:00: A→B 30, до C прямо и через B одинаково — 50
+		{600, [3][3]float64{{0, 30, 50}, {30, 0, 20}, {50, 20, 0}}},
+	}
+	for _, tt := range tests {
+		distMatrix := network.shortestPaths(tt.departure)
+		for i := range tt.want {
+			for j := range tt.want[i] {
+				if got := distMatrix.Dist[i][j]; math.Abs(got-tt.want[i][j]) > 1e-9 {
+					t.Errorf("отправление %s, %s→%s: %v, ожидалось %v",
+						formatSlot(tt.departure), network.vertices[i], network.vertices[j], got, tt.want[i][j])
+				}
+			}
+		}
+	}
+
+	// Выехавший позже не приезжает раньше ни в одну вершину
+	previous := network.shortestPaths(480)
+	for departure := 481.0; departure <= 660; departure++ {
+		current := network.shortestPaths(departure)
+		for i := range current.Dist {
+			for j := range current.Dist[i] {
+				if departure+current.Dist[i][j] < departure-1+previous.Dist[i][j]-1e-9 {
+					t.Fatalf("%s→%s: отправление в %v даёт прибытие раньше, чем минутой ранее",
+						network.vertices[i], network.vertices[j], departure)
+				}
+			}
+		}
+		previous = current
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// Функция для загрузки гипотетических рёбер из CSV-файла со строками
+// "ребро,normal,E,Omega" или "ребро,uniform,a,b". Строки, начинающиеся
+// с #, считаются комментариями. Вершина, которой нет в сети, добавляется
+// как новая вершина-кандидат.
+func loadWhatIf(filename string) ([]edgeDistribution, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("файл изменений %s: %v", filename, err)
+	}
+
+	var overlay []edgeDistribution
+	for _, record := range records {
+		parts := strings.Split(strings.TrimSpace(record[0]), ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" || parts[0] == parts[1] {
+			return nil, fmt.Errorf("некорректное ребро %q, ожидается вида 2:6", record[0])
+		}
+		first, err1 := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		second, err2 := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("ребро %s: некорректные параметры распределения", record[0])
+		}
+
+		d := edgeDistribution{edge: edge{origin: parts[0], destination: parts[1]}}
+		switch strings.TrimSpace(record[1]) {
+		case "normal":
+			if first < 0 || second < 0 {
+				return nil, fmt.Errorf("ребро %s: среднее и отклонение не могут быть отрицательными", record[0])
+			}
+			d.normal, d.mean, d.sd = true, first, second
+		case "uniform":
+			if first < 0 || second < first {
+				return nil, fmt.Errorf("ребро %s: нужно 0 <= a <= b", record[0])
+			}
+			d.a, d.b, d.mean = first, second, (first+second)/2
+		default:
+			return nil, fmt.Errorf("ребро %s: неизвестное распределение %q, доступны: normal, uniform", record[0], record[1])
+		}
+		overlay = append(overlay, d)
+	}
+	return overlay, nil
+}
+
+// Функция для наложения гипотетических рёбер на сеть. Существующее ребро
+// (в любом направлении) получает новое распределение, новое — добавляется
+// вместе с недостающими вершинами.
+func applyWhatIf(points []string, distribution, overlay []edgeDistribution) ([]string, []edgeDistribution) {
+	points, distribution = slices.Clone(points), slices.Clone(distribution)
+	for _, d := range overlay {
+		k := slices.IndexFunc(distribution, func(existing edgeDistribution) bool {
+			return existing.edge == d.edge || existing.edge == edge{origin: d.destination, destination: d.origin}
+		})
+		if k >= 0 {
+			d.edge = distribution[k].edge
+			distribution[k] = d
+		} else {
+			distribution = append(distribution, d)
+		}
+		for _, v := range []string{d.origin, d.destination} {
+			if !slices.Contains(points, v) {
+				points = append(points, v)
+			}
+		}
+	}
+	sortVertexIDs(points)
+	return points, distribution
+}
+
+// Функция для построения таблицы сравнения моделирования исходной сети
+// и сети с изменениями по вершинам
+func whatIfTable(baselinePoints []string, baseline placementSimulation, points []string, variant placementSimulation) [][]string {
+	table := [][]string{{
+		"Вершина",
+		"Доля побед: сеть", "Доля побед: с изменениями", "Изменение доли",
+		"Внешний радиус: сеть", "Внешний радиус: с изменениями",
+		"Внутренний радиус: сеть", "Внутренний радиус: с изменениями",
+		"Критерий: сеть", "Критерий: с изменениями",
+	}}
+	summary := func(simulation placementSimulation, vertices []string, vertex string) (share, external, internal, value float64) {
+		i := slices.Index(vertices, vertex)
+		if i < 0 {
+			return math.NaN(), math.NaN(), math.NaN(), math.NaN()
+		}
+		share = float64(simulation.wins[vertex]) / float64(simulation.iterations)
+		external, internal = meanOf(simulation.externalRadii[i]), meanOf(simulation.internalRadii[i])
+		value = meanOf(eccentricityValues(simulation.externalRadii[i:i+1], simulation.internalRadii[i:i+1])[0])
+		return share, external, internal, value
+	}
+	format := func(value float64, layout string) string {
+		if math.IsNaN(value) {
+			return "—"
+		}
+		return fmt.Sprintf(layout, value)
+	}
+
+	for _, vertex := range points {
+		baseShare, baseExternal, baseInternal, baseValue := summary(baseline, baselinePoints, vertex)
+		share, external, internal, value := summary(variant, points, vertex)
+		change := share
+		if !math.IsNaN(baseShare) {
+			change -= baseShare
+		}
+		name := vertex
+		if math.IsNaN(baseShare) {
+			name += " (новая)"
+		}
+		table = append(table, []string{
+			name,
+			format(baseShare, "%.4f"), format(share, "%.4f"), format(change, "%+.4f"),
+			format(baseExternal, "%.2f"), format(external, "%.2f"),
+			format(baseInternal, "%.2f"), format(internal, "%.2f"),
+			format(baseValue, "%.2f"), format(value, "%.2f"),
+		})
+	}
+	return table
+}
+
+// Функция для построения сгруппированной гистограммы долей побед вершин
+// в исходной сети и в сети с изменениями
+func createWhatIfChart(baselinePoints []string, baseline placementSimulation, points []string, variant placementSimulation, title, filename string) {
+	baseShares := make(plotter.Values, len(points))
+	shares := make(plotter.Values, len(points))
+	for i, vertex := range points {
+		if slices.Contains(baselinePoints, vertex) {
+			baseShares[i] = float64(baseline.wins[vertex]) / float64(baseline.iterations)
+		}
+		shares[i] = float64(variant.wins[vertex]) / float64(variant.iterations)
+	}
+
+	p := plot.New()
+	p.Title.Text = title
+	p.X.Label.Text = "Номер вершины"
+	p.Y.Label.Text = "Доля итераций, в которых вершина оптимальна"
+	p.Y.Min = 0
+
+	width := vg.Points(14)
+	baseBars, err := plotter.NewBarChart(baseShares, width)
+	if err != nil {
+		log.Fatalf("Unable to create bar chart: %v", err)
+	}
+	bars, err := plotter.NewBarChart(shares, width)
+	if err != nil {
+		log.Fatalf("Unable to create bar chart: %v", err)
+	}
+	baseBars.Offset, bars.Offset = -width/2, width/2
+	baseBars.Color = color.RGBA{R: 70, G: 130, B: 180, A: 255}
+	bars.Color = color.RGBA{R: 205, G: 92, B: 92, A: 255}
+	p.Add(baseBars, bars)
+	p.Legend.Add("исходная сеть", baseBars)
+	p.Legend.Add("с изменениями", bars)
+	p.Legend.Top = true
+	p.NominalX(points...)
+
+	if err := savePlot(p, 8*vg.Inch, 4*vg.Inch, filename); err != nil {
+		log.Fatalf("Unable to save bar chart: %v", err)
+	}
+}